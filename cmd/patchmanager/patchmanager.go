@@ -8,8 +8,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/openshift/patchmanager/pkg/cmd/chain"
 	"github.com/openshift/patchmanager/pkg/cmd/cleanup"
 
+	"github.com/openshift/patchmanager/pkg/cmd/explain"
+
 	"github.com/openshift/patchmanager/pkg/cmd/list"
 
 	"github.com/openshift/patchmanager/pkg/cmd/approve"
@@ -50,6 +53,8 @@ func NewPatchManagerCommand(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(approve.NewApproveCommand(ctx))
 	cmd.AddCommand(list.NewListCommand(ctx))
 	cmd.AddCommand(cleanup.NewCleanupCommand(ctx))
+	cmd.AddCommand(explain.NewExplainCommand(ctx))
+	cmd.AddCommand(chain.NewChainCommand(ctx))
 
 	return cmd
 }
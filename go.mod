@@ -10,6 +10,7 @@ require (
 	github.com/enriquebris/goworkerpool v0.10.0
 	github.com/eparis/bugzilla v0.0.0-20210108140723-998a521ca0b5
 	github.com/fatih/color v1.7.0
+	github.com/google/cel-go v0.12.6
 	github.com/google/go-github/v32 v32.1.0
 	github.com/kataras/tablewriter v0.0.0-20180708051242-e063d29b7c23 // indirect
 	github.com/lensesio/tableprinter v0.0.0-20201125135848-89e81fc956e7
@@ -18,6 +19,7 @@ require (
 	github.com/spf13/pflag v1.0.5
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	gopkg.in/yaml.v2 v2.4.0
+	k8s.io/apimachinery v0.20.4
 	k8s.io/component-base v0.20.4
 	k8s.io/klog/v2 v2.4.0
 )
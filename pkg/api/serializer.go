@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	v1 "github.com/openshift/patchmanager/pkg/api/v1"
@@ -14,6 +15,11 @@ func NewCandidateList(candidates []v1.Candidate) v1.CandidateList {
 	items := make([]v1.Candidate, len(candidates))
 
 	for i := range candidates {
+		// bugs that could not be fetched have no URL
+		bugURL := candidates[i].BugURL
+		if len(bugURL) == 0 {
+			bugURL = candidates[i].BugNumber
+		}
 		items[i] = v1.Candidate{
 			CommentedMapSlice: yaml.CommentedMapSlice{
 				{
@@ -23,7 +29,7 @@ Bug: %s
 Component: %s
 Severity: %s
 PM Score %s
-`, sanitizeSummary(candidates[i].Description), fmt.Sprintf("https://bugzilla.redhat.com/show_bug.cgi?id=%s", candidates[i].BugNumber), candidates[i].Component, candidates[i].Severity, candidates[i].PMScore),
+`, sanitizeSummary(candidates[i].Description), bugURL, candidates[i].Component, candidates[i].Severity, candidates[i].PMScore),
 				},
 				{
 					MapItem: yaml.MapItem{Key: "decision", Value: candidates[i].Decision},
@@ -33,13 +39,58 @@ PM Score %s
 				},
 			},
 		}
+		if candidates[i].Cost != 0 {
+			items[i].CommentedMapSlice = append(items[i].CommentedMapSlice, yaml.CommentedMapItem{
+				MapItem: yaml.MapItem{Key: "cost", Value: candidates[i].Cost},
+			})
+		}
+		if breakdown := scoreBreakdownMapSlice(candidates[i].ScoreBreakdown); len(breakdown) > 0 {
+			items[i].CommentedMapSlice = append(items[i].CommentedMapSlice, yaml.CommentedMapItem{
+				MapItem: yaml.MapItem{Key: "scoreBreakdown", Value: breakdown},
+			})
+		}
+		if len(candidates[i].Threshold) > 0 {
+			items[i].CommentedMapSlice = append(items[i].CommentedMapSlice, yaml.CommentedMapItem{
+				MapItem: yaml.MapItem{Key: "threshold", Value: candidates[i].Threshold},
+			})
+		}
 		if len(candidates[i].DecisionReason) > 0 {
 			items[i].CommentedMapSlice = append(items[i].CommentedMapSlice, yaml.CommentedMapItem{
 				MapItem: yaml.MapItem{Key: "decisionReason", Value: candidates[i].DecisionReason},
 			})
 		}
 	}
-	return v1.CandidateList{items}
+	return v1.CandidateList{Items: items}
+}
+
+// scoreBreakdownMapSlice returns non-zero score contributions sorted by the classifier name, so the output is stable.
+func scoreBreakdownMapSlice(breakdown map[string]float32) yaml.MapSlice {
+	result := yaml.MapSlice{}
+	for _, name := range sortedNames(breakdown) {
+		if breakdown[name] == 0 {
+			continue
+		}
+		result = append(result, yaml.MapItem{Key: name, Value: breakdown[name]})
+	}
+	return result
+}
+
+// FormatScoreBreakdown returns human readable list of non-zero score contributions (eg. "severity: 1.00, keywords: 0.80").
+func FormatScoreBreakdown(breakdown map[string]float32) string {
+	result := []string{}
+	for _, item := range scoreBreakdownMapSlice(breakdown) {
+		result = append(result, fmt.Sprintf("%s: %0.2f", item.Key, item.Value))
+	}
+	return strings.Join(result, ", ")
+}
+
+func sortedNames(m map[string]float32) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func sanitizeSummary(in string) string {
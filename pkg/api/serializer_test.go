@@ -0,0 +1,84 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	v1 "github.com/openshift/patchmanager/pkg/api/v1"
+)
+
+func TestNewCandidateListBugComment(t *testing.T) {
+	list := NewCandidateList([]v1.Candidate{
+		{PullRequestURL: "https://github.com/openshift/origin/pull/1", BugNumber: "1234", BugURL: "https://bugzilla.redhat.com/show_bug.cgi?id=1234", Decision: "pick"},
+		{PullRequestURL: "https://github.com/openshift/origin/pull/2", BugNumber: "OCPBUGS-2", Decision: "error"},
+	})
+	for i, expected := range []string{"Bug: https://bugzilla.redhat.com/show_bug.cgi?id=1234\n", "Bug: OCPBUGS-2\n"} {
+		if comment := list.Items[i].CommentedMapSlice[0].Comment; !strings.Contains(comment, expected) {
+			t.Errorf("expected comment of candidate %d to contain %q, got %q", i, expected, comment)
+		}
+	}
+}
+
+func TestNewCandidateListCost(t *testing.T) {
+	list := NewCandidateList([]v1.Candidate{
+		{PullRequestURL: "https://github.com/openshift/origin/pull/1", Decision: "pick", Cost: 1.5},
+		{PullRequestURL: "https://github.com/openshift/origin/pull/2", Decision: "pick"},
+	})
+	for i, expected := range []bool{true, false} {
+		found := false
+		for _, item := range list.Items[i].CommentedMapSlice {
+			found = found || item.Key == "cost"
+		}
+		if found != expected {
+			t.Errorf("expected cost of candidate %d to be serialized: %v", i, expected)
+		}
+	}
+}
+
+func TestNewCandidateListScoreBreakdown(t *testing.T) {
+	list := NewCandidateList([]v1.Candidate{
+		{
+			PullRequestURL: "https://github.com/openshift/origin/pull/1",
+			Decision:       "pick",
+			Score:          1.5,
+			ScoreBreakdown: map[string]float32{"severity": 1, "keywords": 0.75, "risk": -0.25, "priority": 0},
+		},
+		{PullRequestURL: "https://github.com/openshift/origin/pull/2", Decision: "skip", ScoreBreakdown: map[string]float32{"severity": 0}},
+	})
+	out, err := yaml.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// contributions are sorted by the classifier name and zero contributions are omitted
+	expected := "scoreBreakdown:\n      keywords: 0.75\n      risk: -0.25\n      severity: 1\n"
+	if !strings.Contains(string(out), expected) {
+		t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+	}
+	if strings.Count(string(out), "scoreBreakdown") != 1 {
+		t.Errorf("expected score breakdown to be omitted when all contributions are zero, got:\n%s", out)
+	}
+
+	// the approve command reads the breakdown back
+	var approved v1.ApprovedCandidateList
+	if err := yaml.Unmarshal(out, &approved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedBreakdown := map[string]float32{"keywords": 0.75, "risk": -0.25, "severity": 1}
+	if breakdown := approved.Items[0].PullRequest.ScoreBreakdown; !reflect.DeepEqual(breakdown, expectedBreakdown) {
+		t.Errorf("expected score breakdown %v, got %v", expectedBreakdown, breakdown)
+	}
+	if breakdown := approved.Items[1].PullRequest.ScoreBreakdown; breakdown != nil {
+		t.Errorf("expected no score breakdown, got %v", breakdown)
+	}
+}
+
+func TestFormatScoreBreakdown(t *testing.T) {
+	formatted := FormatScoreBreakdown(map[string]float32{"severity": 1, "keywords": 0.8, "priority": 0})
+	if expected := "keywords: 0.80, severity: 1.00"; formatted != expected {
+		t.Errorf("expected %q, got %q", expected, formatted)
+	}
+}
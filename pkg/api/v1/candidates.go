@@ -13,21 +13,52 @@ type CandidateList struct {
 type Candidate struct {
 	yaml.CommentedMapSlice `yaml:"pullRequest"`
 
-	Decision       string  `yaml:"-"`
-	DecisionReason string  `yaml:"-"`
-	PMScore        string  `yaml:"-"`
-	Score          float32 `yaml:"-"`
-	Description    string  `yaml:"-"`
-	PullRequestURL string  `yaml:"-"`
-	BugNumber      string  `yaml:"-"`
-	Component      string  `yaml:"-"`
-	Severity       string  `yaml:"-"`
+	Decision       string             `yaml:"-"`
+	DecisionReason string             `yaml:"-"`
+	Threshold      string             `yaml:"-"`
+	Cost           float32            `yaml:"-"`
+	PMScore        string             `yaml:"-"`
+	Score          float32            `yaml:"-"`
+	ScoreBreakdown map[string]float32 `yaml:"-"`
+	Description    string             `yaml:"-"`
+	PullRequestURL string             `yaml:"-"`
+	BugNumber      string             `yaml:"-"`
+	BugURL         string             `yaml:"-"`
+	Component      string             `yaml:"-"`
+	Severity       string             `yaml:"-"`
+}
+
+const (
+	// ThresholdMinimumScore is recorded for candidates skipped because of score lower than the minimum score.
+	ThresholdMinimumScore = "minScore"
+	// ThresholdMustPick is recorded for candidates picked over their component capacity because of the must-pick score.
+	ThresholdMustPick = "mustPickScore"
+)
+
+// ReleaseSummaryList summarize the candidates of all releases triaged in a single run.
+type ReleaseSummaryList struct {
+	Items []ReleaseSummary `yaml:"items"`
+}
+
+// ReleaseSummary describe the candidates of a single release and the shared capacity points they take.
+type ReleaseSummary struct {
+	Release        string `yaml:"release"`
+	Priority       int    `yaml:"priority"`
+	CandidatesFile string `yaml:"candidatesFile,omitempty"`
+	// SearchTotal is the number of search results reported by Github, Truncated is set when not all of them were returned.
+	SearchTotal int     `yaml:"searchTotal"`
+	Truncated   bool    `yaml:"truncated,omitempty"`
+	Total       int     `yaml:"total"`
+	Picks       int     `yaml:"picks"`
+	Skips       int     `yaml:"skips"`
+	Errors      int     `yaml:"errors"`
+	Cost        float32 `yaml:"cost"`
 }
 
 // ApprovedCandidateList represents a list of approved candidates
 // This is used for parsing candidate list YAML, ignoring YAML comments.
 type ApprovedCandidateList struct {
-	Items []ApprovedCandidate `yaml:items`
+	Items []ApprovedCandidate `yaml:"items"`
 }
 
 type ApprovedCandidate struct {
@@ -35,8 +66,11 @@ type ApprovedCandidate struct {
 }
 
 type ApprovedPullRequest struct {
-	URL            string  `yaml:"url"`
-	Decision       string  `yaml:"decision"`
-	DecisionReason string  `yaml:"decisionReason"`
-	Score          float32 `yaml:"score"`
+	URL            string             `yaml:"url"`
+	Decision       string             `yaml:"decision"`
+	DecisionReason string             `yaml:"decisionReason"`
+	Threshold      string             `yaml:"threshold"`
+	Cost           float32            `yaml:"cost"`
+	Score          float32            `yaml:"score"`
+	ScoreBreakdown map[string]float32 `yaml:"scoreBreakdown"`
 }
@@ -0,0 +1,272 @@
+package backport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+// maxChainLength limits how many bugs are followed in the clone chain, to not walk unrelated bugs forever.
+const maxChainLength = 20
+
+const (
+	StateMerged   = "merged"
+	StateApproved = "approved"
+	StateOpen     = "open"
+	StateClosed   = "closed"
+	StateError    = "error"
+)
+
+// PullRequestState is a pull request linked to a bug in the clone chain.
+type PullRequestState struct {
+	URL   string
+	State string
+	Err   error
+}
+
+// Step is a single bug in the clone chain.
+type Step struct {
+	BugID string
+	// Bug is nil when the bug can't be fetched
+	Bug *tracker.Issue
+	Err error
+	// Release is the minor release the bug targets (eg. 4.7), empty when the bug has no target release set
+	Release      string
+	PullRequests []PullRequestState
+}
+
+// Merged returns true if any pull request linked to the bug is merged or, when acceptApproved is set, approved.
+func (s *Step) Merged(acceptApproved bool) bool {
+	for _, p := range s.PullRequests {
+		if p.State == StateMerged || (acceptApproved && p.State == StateApproved) {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain is the ladder of bugs cloned from each other across releases, ordered from the newest release.
+type Chain struct {
+	Steps []*Step
+}
+
+// Walker follows the bug clone chain using the bug tracker and Github.
+type Walker struct {
+	GetBug         func(id string) (*tracker.Issue, error)
+	GetPullRequest func(url string) (*githubapi.PullRequest, error)
+}
+
+// Walk returns the clone chain of the bug. Parent bugs (DependsOn targeting a newer release) and clones (Blocks targeting
+// an older release) are followed transitively, other dependencies are not part of the chain. Related bugs that can't be
+// fetched are added to the chain (as steps with error) only when no other related bug of the step is its parent or clone,
+// as they might be unrelated (eg. private) bugs.
+func (w *Walker) Walk(bug *tracker.Issue) *Chain {
+	chain := &Chain{}
+	visited := map[string]bool{bug.ID: true}
+	// fetched holds visited bugs that are parents or clones, visited bugs that can't be fetched are not
+	fetched := map[string]bool{bug.ID: true}
+	queue := []*Step{w.newStep(bug.ID, bug, nil)}
+	for len(queue) > 0 && len(chain.Steps) < maxChainLength {
+		step := queue[0]
+		queue = queue[1:]
+		chain.Steps = append(chain.Steps, step)
+		if step.Bug == nil {
+			continue
+		}
+		// hasRelated is set when the step has a parent or clone, including those already in the chain
+		hasRelated := false
+		failed := []*Step{}
+		for _, link := range []struct {
+			ids    []string
+			follow func(bug, related *tracker.Issue) bool
+		}{
+			{ids: step.Bug.DependsOn, follow: IsParent},
+			{ids: step.Bug.Blocks, follow: IsClone},
+		} {
+			for _, id := range link.ids {
+				if visited[id] {
+					hasRelated = hasRelated || fetched[id]
+					continue
+				}
+				related, err := w.GetBug(id)
+				if err != nil {
+					failed = append(failed, w.newStep(id, nil, err))
+					continue
+				}
+				if !link.follow(step.Bug, related) {
+					continue
+				}
+				hasRelated = true
+				visited[id], fetched[id] = true, true
+				queue = append(queue, w.newStep(id, related, nil))
+			}
+		}
+		if hasRelated {
+			continue
+		}
+		for _, f := range failed {
+			visited[f.BugID] = true
+			queue = append(queue, f)
+		}
+	}
+	sort.SliceStable(chain.Steps, func(i, j int) bool {
+		return CompareReleases(chain.Steps[i].Release, chain.Steps[j].Release) > 0
+	})
+	return chain
+}
+
+// IsParent returns true when the related bug targets a newer release than the bug, so the bug can be its backport.
+// Bugs in the same release are usually unrelated bugs (eg. tracker bugs), not clones.
+func IsParent(bug, related *tracker.Issue) bool {
+	release, relatedRelease := releaseOf(bug.TargetRelease), releaseOf(related.TargetRelease)
+	return len(release) > 0 && len(relatedRelease) > 0 && CompareReleases(relatedRelease, release) > 0
+}
+
+// IsClone returns true when the related bug targets an older release than the bug, so it can be backport of the bug.
+func IsClone(bug, related *tracker.Issue) bool {
+	return IsParent(related, bug)
+}
+
+func (w *Walker) newStep(id string, bug *tracker.Issue, err error) *Step {
+	step := &Step{BugID: id, Bug: bug, Err: err}
+	if bug == nil {
+		return step
+	}
+	step.Release = releaseOf(bug.TargetRelease)
+	for _, prURL := range bug.PullRequests {
+		state := PullRequestState{URL: prURL}
+		pull, err := w.GetPullRequest(prURL)
+		switch {
+		case err != nil:
+			state.State, state.Err = StateError, err
+		case pull.GetMerged():
+			state.State = StateMerged
+		case pull.GetState() == "closed":
+			state.State = StateClosed
+		case hasLabel(pull, "cherry-pick-approved"):
+			state.State = StateApproved
+		default:
+			state.State = StateOpen
+		}
+		step.PullRequests = append(step.PullRequests, state)
+	}
+	return step
+}
+
+// Gaps returns descriptions of bugs in the chain that can't be fetched (eg. private bugs), as the chain can't be verified
+// without them, and of releases newer than the given release where the fix is missing or not merged.
+func (c *Chain) Gaps(release string, acceptApproved bool) []string {
+	gaps := []string{}
+	steps := map[string][]*Step{}
+	newest := release
+	for _, s := range c.Steps {
+		if s.Err != nil {
+			gaps = append(gaps, fmt.Sprintf("bug %s in the backport chain can't be fetched: %v", s.BugID, s.Err))
+			continue
+		}
+		if len(s.Release) == 0 {
+			continue
+		}
+		steps[s.Release] = append(steps[s.Release], s)
+		if CompareReleases(s.Release, newest) > 0 {
+			newest = s.Release
+		}
+	}
+
+	for _, r := range releasesBetween(release, newest) {
+		if len(steps[r]) == 0 {
+			gaps = append(gaps, fmt.Sprintf("%s backport missing", r))
+			continue
+		}
+		merged := false
+		bugIDs := []string{}
+		for _, s := range steps[r] {
+			merged = merged || s.Merged(acceptApproved)
+			bugIDs = append(bugIDs, s.BugID)
+		}
+		if !merged {
+			gaps = append(gaps, fmt.Sprintf("%s backport not merged (bug %s)", r, strings.Join(bugIDs, ", ")))
+		}
+	}
+	return gaps
+}
+
+func hasLabel(pull *githubapi.PullRequest, name string) bool {
+	for _, l := range pull.Labels {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseOf returns the minor release (eg. 4.7) from bug target release (eg. 4.7.z or 4.7.0).
+func releaseOf(targetRelease []string) string {
+	for _, t := range targetRelease {
+		parts := strings.Split(t, ".")
+		if len(parts) < 2 {
+			continue
+		}
+		if _, _, ok := parseRelease(parts[0] + "." + parts[1]); ok {
+			return parts[0] + "." + parts[1]
+		}
+	}
+	return ""
+}
+
+func parseRelease(release string) (int, int, bool) {
+	parts := strings.Split(release, ".")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// CompareReleases returns positive number when release a is newer than b, negative when older and 0 when they are equal.
+// Releases that are not in major.minor format are older than any other release.
+func CompareReleases(a, b string) int {
+	aMajor, aMinor, aOK := parseRelease(a)
+	bMajor, bMinor, bOK := parseRelease(b)
+	switch {
+	case !aOK && !bOK:
+		return 0
+	case !aOK:
+		return -1
+	case !bOK:
+		return 1
+	case aMajor != bMajor:
+		return aMajor - bMajor
+	default:
+		return aMinor - bMinor
+	}
+}
+
+// releasesBetween returns releases newer than from up to (including) to within the same major version.
+func releasesBetween(from, to string) []string {
+	fromMajor, fromMinor, ok := parseRelease(from)
+	if !ok {
+		return nil
+	}
+	toMajor, toMinor, ok := parseRelease(to)
+	if !ok || toMajor != fromMajor {
+		return nil
+	}
+	result := []string{}
+	for minor := fromMinor + 1; minor <= toMinor; minor++ {
+		result = append(result, fmt.Sprintf("%d.%d", fromMajor, minor))
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package backport
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func testWalker(bugs map[string]*tracker.Issue, merged map[string]bool) *Walker {
+	return &Walker{
+		GetBug: func(id string) (*tracker.Issue, error) {
+			if bug, ok := bugs[id]; ok {
+				return bug, nil
+			}
+			return nil, &tracker.NotAccessibleError{Tracker: tracker.Bugzilla, ID: id, Err: fmt.Errorf("not found")}
+		},
+		GetPullRequest: func(url string) (*githubapi.PullRequest, error) {
+			return &githubapi.PullRequest{Merged: githubapi.Bool(merged[url]), State: githubapi.String("open")}, nil
+		},
+	}
+}
+
+func TestWalkFollowsOnlyClones(t *testing.T) {
+	bugs := map[string]*tracker.Issue{
+		// 4.6 backport of 4.7 bug, also depending on an unrelated 4.6 tracker bug
+		"1": {ID: "1", TargetRelease: []string{"4.6.z"}, DependsOn: []string{"2", "10"}, PullRequests: []string{"pr-4.6"}},
+		"2": {ID: "2", TargetRelease: []string{"4.7.z"}, DependsOn: []string{"3"}, Blocks: []string{"1", "11"}, PullRequests: []string{"pr-4.7"}},
+		"3": {ID: "3", TargetRelease: []string{"4.8.0"}, Blocks: []string{"2"}, PullRequests: []string{"pr-4.8"}},
+		// unrelated tracker bug in the same release
+		"10": {ID: "10", TargetRelease: []string{"4.6.z"}},
+		// unrelated newer bug blocked by the 4.7 bug
+		"11": {ID: "11", TargetRelease: []string{"4.10.0"}},
+	}
+	chain := testWalker(bugs, map[string]bool{"pr-4.7": true, "pr-4.8": true}).Walk(bugs["1"])
+	ids := []string{}
+	for _, s := range chain.Steps {
+		ids = append(ids, s.BugID)
+	}
+	if !reflect.DeepEqual(ids, []string{"3", "2", "1"}) {
+		t.Errorf("expected chain 3, 2, 1, got %v", ids)
+	}
+	if gaps := chain.Gaps("4.6", false); len(gaps) != 0 {
+		t.Errorf("expected no gaps, got %v", gaps)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	bugs := map[string]*tracker.Issue{
+		"1": {ID: "1", TargetRelease: []string{"4.6.z"}, DependsOn: []string{"2"}},
+		"2": {ID: "2", TargetRelease: []string{"4.7.z"}, DependsOn: []string{"3"}, PullRequests: []string{"pr-4.7"}},
+		"3": {ID: "3", TargetRelease: []string{"4.9.0"}, PullRequests: []string{"pr-4.9"}},
+	}
+	gaps := testWalker(bugs, map[string]bool{"pr-4.9": true}).Walk(bugs["1"]).Gaps("4.6", false)
+	expected := []string{"4.7 backport not merged (bug 2)", "4.8 backport missing"}
+	if !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("expected %v, got %v", expected, gaps)
+	}
+}
+
+func TestWalkIgnoresUnrelatedFetchErrors(t *testing.T) {
+	bugs := map[string]*tracker.Issue{
+		// 4.6 backport blocking a private flaw bug
+		"1": {ID: "1", TargetRelease: []string{"4.6.z"}, DependsOn: []string{"2"}, Blocks: []string{"20"}},
+		// 4.7 backport blocking the 4.6 backport and another private bug
+		"2": {ID: "2", TargetRelease: []string{"4.7.z"}, DependsOn: []string{"3"}, Blocks: []string{"1", "21"}, PullRequests: []string{"pr-4.7"}},
+		"3": {ID: "3", TargetRelease: []string{"4.8.0"}, Blocks: []string{"2"}, PullRequests: []string{"pr-4.8"}},
+		// parent bug can't be fetched and there is no other parent
+		"4": {ID: "4", TargetRelease: []string{"4.6.z"}, DependsOn: []string{"22"}, Blocks: []string{"20"}},
+	}
+	walker := testWalker(bugs, map[string]bool{"pr-4.7": true, "pr-4.8": true})
+	if gaps := walker.Walk(bugs["1"]).Gaps("4.6", false); len(gaps) != 0 {
+		t.Errorf("expected no gaps, got %v", gaps)
+	}
+
+	expected := []string{
+		"bug 22 in the backport chain can't be fetched: bugzilla issue 22 is not accessible: not found",
+		"bug 20 in the backport chain can't be fetched: bugzilla issue 20 is not accessible: not found",
+	}
+	if gaps := walker.Walk(bugs["4"]).Gaps("4.6", false); !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("expected %v, got %v", expected, gaps)
+	}
+}
@@ -0,0 +1,38 @@
+package capacity
+
+import (
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// Cost returns the capacity points the pull request takes when picked.
+func Cost(c *config.CostConfig, pullRequest *github.PullRequest, component, repo string) float32 {
+	cost := c.Base
+	if cost == 0 {
+		cost = 1
+	}
+	if c.PerHundredLines != 0 {
+		if details, err := pullRequest.Details(); err != nil {
+			klog.Warningf("WARNING: Unable to get details of %s: %v", pullRequest.Issue.GetHTMLURL(), err)
+		} else {
+			cost += float32(details.GetAdditions()+details.GetDeletions()) / 100 * c.PerHundredLines
+		}
+	}
+	cost *= factor(c.Severities, pullRequest.Bug().Severity)
+	cost *= factor(c.Repos, repo)
+	cost *= factor(c.Components, component)
+	return cost
+}
+
+func factor(factors map[string]float32, name string) float32 {
+	for n, f := range factors {
+		if strings.EqualFold(n, name) {
+			return f
+		}
+	}
+	return 1
+}
@@ -0,0 +1,79 @@
+package capacity
+
+import (
+	"testing"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestCost(t *testing.T) {
+	const prURL = "https://github.com/openshift/kubernetes/pull/1"
+	tests := []struct {
+		name     string
+		config   config.CostConfig
+		details  *githubapi.PullRequest
+		expected float32
+	}{
+		{
+			name:     "default cost",
+			expected: 1,
+		},
+		{
+			name:     "base",
+			config:   config.CostConfig{Base: 2},
+			expected: 2,
+		},
+		{
+			name:     "changed lines",
+			config:   config.CostConfig{PerHundredLines: 0.5},
+			details:  &githubapi.PullRequest{Additions: githubapi.Int(150), Deletions: githubapi.Int(50)},
+			expected: 2,
+		},
+		{
+			name:     "details can't be fetched",
+			config:   config.CostConfig{Base: 0.5, PerHundredLines: 0.5},
+			expected: 0.5,
+		},
+		{
+			name: "severity, repository and component factors",
+			config: config.CostConfig{
+				Severities: map[string]float32{"Urgent": 1.5, "low": 0.5},
+				Repos:      map[string]float32{"openshift/kubernetes": 2},
+				Components: map[string]float32{"node": 0.5, "networking": 3},
+			},
+			expected: 1.5,
+		},
+		{
+			name: "all terms",
+			config: config.CostConfig{
+				Base:            0.5,
+				PerHundredLines: 1,
+				Severities:      map[string]float32{"urgent": 2},
+				Repos:           map[string]float32{"openshift/origin": 3},
+				Components:      map[string]float32{"Node": 1.5},
+			},
+			details:  &githubapi.PullRequest{Additions: githubapi.Int(40), Deletions: githubapi.Int(10)},
+			expected: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pulls := map[string]*githubapi.PullRequest{}
+			if test.details != nil {
+				pulls[prURL] = test.details
+			}
+			pullRequest := fake.NewPullRequest(fake.PullRequest{
+				Issue:        &githubapi.Issue{HTMLURL: githubapi.String(prURL)},
+				Bug:          &tracker.Issue{ID: "1", Severity: "urgent"},
+				RelatedPulls: pulls,
+			})
+			if cost := Cost(&test.config, pullRequest, "node", "openshift/kubernetes"); cost != test.expected {
+				t.Errorf("expected cost %g, got %g", test.expected, cost)
+			}
+		})
+	}
+}
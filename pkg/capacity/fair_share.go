@@ -0,0 +1,57 @@
+package capacity
+
+import (
+	"sort"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+// FairShares divides the budget (capacity points) among the component groups according to the configured allocation.
+// With "roundRobin" allocation the points are dealt to the groups in turn, with "weighted" allocation every group gets
+// share proportional to its weight (the points left after rounding go to the groups with the largest remainders).
+// Other allocations give no shares.
+func FairShares(c *config.CapacityConfig, budget int) map[string]int {
+	shares := map[string]int{}
+	if len(c.Groups) == 0 || budget <= 0 {
+		return shares
+	}
+
+	weights := make([]int, len(c.Groups))
+	totalWeight := 0
+	for i, group := range c.Groups {
+		switch c.Allocation {
+		case config.RoundRobinAllocation:
+			weights[i] = 1
+		case config.WeightedAllocation:
+			weights[i] = group.Weight
+			if weights[i] <= 0 {
+				weights[i] = group.Capacity
+			}
+		default:
+			return shares
+		}
+		totalWeight += weights[i]
+	}
+	if totalWeight == 0 {
+		return shares
+	}
+
+	remainders := make([]int, len(c.Groups))
+	order := make([]int, len(c.Groups))
+	dealt := 0
+	for i, group := range c.Groups {
+		shares[group.Name] = budget * weights[i] / totalWeight
+		remainders[i] = budget * weights[i] % totalWeight
+		order[i] = i
+		dealt += shares[group.Name]
+	}
+	// groups with the same remainder get the points left in the order they are configured
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for i := 0; dealt < budget; i++ {
+		shares[c.Groups[order[i]].Name]++
+		dealt++
+	}
+	return shares
+}
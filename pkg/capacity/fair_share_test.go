@@ -0,0 +1,85 @@
+package capacity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+func TestFairShares(t *testing.T) {
+	groups := []config.ComponentGroup{
+		{Name: "a", Capacity: 6},
+		{Name: "b", Capacity: 3, Weight: 1},
+		{Name: "c", Capacity: 1},
+	}
+	tests := []struct {
+		name       string
+		allocation string
+		groups     []config.ComponentGroup
+		budget     int
+		expected   map[string]int
+	}{
+		{
+			name:       "score allocation gives no shares",
+			allocation: config.ScoreAllocation,
+			groups:     groups,
+			budget:     10,
+			expected:   map[string]int{},
+		},
+		{
+			name:       "round robin",
+			allocation: config.RoundRobinAllocation,
+			groups:     groups,
+			budget:     9,
+			expected:   map[string]int{"a": 3, "b": 3, "c": 3},
+		},
+		{
+			name:       "round robin points left go to groups in the configured order",
+			allocation: config.RoundRobinAllocation,
+			groups:     groups,
+			budget:     5,
+			expected:   map[string]int{"a": 2, "b": 2, "c": 1},
+		},
+		{
+			name:       "weighted by weight or capacity",
+			allocation: config.WeightedAllocation,
+			groups:     groups,
+			budget:     16,
+			// weights 6, 1 and 1: 12, 2 and 2
+			expected: map[string]int{"a": 12, "b": 2, "c": 2},
+		},
+		{
+			name:       "weighted largest remainder",
+			allocation: config.WeightedAllocation,
+			groups: []config.ComponentGroup{
+				{Name: "a", Weight: 5},
+				{Name: "b", Weight: 3},
+				{Name: "c", Weight: 2},
+			},
+			budget: 4,
+			// exact shares 2, 1.2 and 0.8: the point left goes to c with the largest remainder
+			expected: map[string]int{"a": 2, "b": 1, "c": 1},
+		},
+		{
+			name:       "no budget",
+			allocation: config.RoundRobinAllocation,
+			groups:     groups,
+			expected:   map[string]int{},
+		},
+		{
+			name:       "no groups",
+			allocation: config.RoundRobinAllocation,
+			budget:     10,
+			expected:   map[string]int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &config.CapacityConfig{Allocation: test.allocation, Groups: test.groups}
+			if shares := FairShares(c, test.budget); !reflect.DeepEqual(shares, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, shares)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package capacity
+
+import (
+	"sort"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+// solverNodeLimit limits the number of branches the solver explores, the best selection found so far is returned when
+// the limit is reached.
+var solverNodeLimit = 5000000
+
+// Item is a candidate for the optimal selection.
+type Item struct {
+	Component string
+	Repo      string
+	// Cost is the number of capacity points the item takes.
+	Cost float32
+	// Value is the gain of picking the item (must be positive for the item to be picked).
+	Value float64
+	// MustPick items are picked regardless of group, component and repository capacity.
+	MustPick bool
+}
+
+// Solve returns the selection of items with the highest total value that fits into group, component, repository and
+// total capacity points not yet used by picks in the tracker (0/1 multi-constrained knapsack solved by branch and bound).
+// The second return value is false when the solver reached the node limit and the selection may not be optimal.
+func Solve(c *config.CapacityConfig, items []Item, maxTotal int, used *Tracker) ([]bool, bool) {
+	s := &solver{config: c, picked: make([]bool, len(items)), constraints: map[string]int{}}
+	total := s.constraint("total", maxTotal, used.Used())
+
+	// must-pick items are picked first in the order of their value and only consume the capacity, the search explores
+	// other items with the best value per capacity point first
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := items[order[i]], items[order[j]]
+		switch {
+		case a.MustPick != b.MustPick:
+			return a.MustPick
+		case a.MustPick:
+			return a.Value > b.Value
+		default:
+			return a.Value/float64(a.Cost) > b.Value/float64(b.Cost)
+		}
+	})
+	for _, i := range order {
+		cons := s.itemConstraints(items[i], total, used)
+		cost := float64(items[i].Cost)
+		if !items[i].MustPick || s.remaining[total] < cost-epsilon {
+			if !items[i].MustPick && items[i].Value > 0 {
+				s.items = append(s.items, solverItem{index: i, value: items[i].Value, cost: cost, constraints: cons})
+			}
+			continue
+		}
+		s.picked[i] = true
+		for _, c := range cons {
+			s.remaining[c] -= cost
+		}
+	}
+
+	s.current = make([]bool, len(s.items))
+	s.best = make([]bool, len(s.items))
+	s.search(0, 0)
+	for j, item := range s.items {
+		s.picked[item.index] = s.best[j]
+	}
+	return s.picked, s.nodes < solverNodeLimit
+}
+
+type solverItem struct {
+	index       int
+	value       float64
+	cost        float64
+	constraints []int
+}
+
+type solver struct {
+	config *config.CapacityConfig
+	// constraints maps constraint names to indexes in remaining
+	constraints map[string]int
+	remaining   []float64
+
+	items     []solverItem
+	picked    []bool
+	current   []bool
+	best      []bool
+	bestValue float64
+	nodes     int
+}
+
+func (s *solver) constraint(name string, capacity int, used float32) int {
+	if i, ok := s.constraints[name]; ok {
+		return i
+	}
+	s.constraints[name] = len(s.remaining)
+	s.remaining = append(s.remaining, float64(capacity)-float64(used))
+	return s.constraints[name]
+}
+
+// itemConstraints returns indexes of all capacity constraints the item counts into.
+func (s *solver) itemConstraints(item Item, total int, used *Tracker) []int {
+	result := []int{total}
+	group := config.ComponentGroupFor(s.config, item.Component)
+	componentPoints := used.componentPoints[item.Component]
+	if group == nil {
+		result = append(result, s.constraint("component/"+item.Component, s.config.MaximumDefaultPicksPerComponent, componentPoints))
+	} else {
+		result = append(result, s.constraint("group/"+group.Name, group.Capacity, used.groupPoints[group.Name]))
+		if limited, limit := config.ComponentLimit(group, item.Component); limited {
+			result = append(result, s.constraint("component/"+item.Component, limit, componentPoints))
+		}
+	}
+	if s.config.MaximumPicksPerRepo > 0 {
+		result = append(result, s.constraint("repo/"+item.Repo, s.config.MaximumPicksPerRepo, used.repoPoints[item.Repo]))
+	}
+	return result
+}
+
+func (s *solver) fits(item solverItem) bool {
+	for _, c := range item.constraints {
+		if s.remaining[c] < item.cost-epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// bound returns the upper bound of value that can be added by items from i. It is the fractional knapsack of the items
+// that fit, ignoring how the items share constraints other than the total capacity.
+func (s *solver) bound(i int) float64 {
+	total := s.remaining[s.constraints["total"]]
+	result := float64(0)
+	for j := i; j < len(s.items) && total > epsilon; j++ {
+		if !s.fits(s.items[j]) {
+			continue
+		}
+		if s.items[j].cost <= total {
+			result += s.items[j].value
+			total -= s.items[j].cost
+			continue
+		}
+		result += s.items[j].value * total / s.items[j].cost
+		total = 0
+	}
+	return result
+}
+
+func (s *solver) search(i int, value float64) {
+	s.nodes++
+	if value > s.bestValue {
+		s.bestValue = value
+		copy(s.best, s.current)
+	}
+	if i == len(s.items) || s.nodes >= solverNodeLimit {
+		return
+	}
+	if value+s.bound(i) <= s.bestValue {
+		return
+	}
+	item := s.items[i]
+	if s.fits(item) {
+		for _, c := range item.constraints {
+			s.remaining[c] -= item.cost
+		}
+		s.current[i] = true
+		s.search(i+1, value+item.value)
+		s.current[i] = false
+		for _, c := range item.constraints {
+			s.remaining[c] += item.cost
+		}
+	}
+	s.search(i+1, value)
+}
@@ -0,0 +1,230 @@
+package capacity
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+// constraintKeys returns the capacity constraints the item counts into and their capacity.
+func constraintKeys(c *config.CapacityConfig, item Item, maxTotal int) map[string]int {
+	keys := map[string]int{"total": maxTotal}
+	if group := config.ComponentGroupFor(c, item.Component); group == nil {
+		keys["component/"+item.Component] = c.MaximumDefaultPicksPerComponent
+	} else {
+		keys["group/"+group.Name] = group.Capacity
+		if limited, limit := config.ComponentLimit(group, item.Component); limited {
+			keys["component/"+item.Component] = limit
+		}
+	}
+	if c.MaximumPicksPerRepo > 0 {
+		keys["repo/"+item.Repo] = c.MaximumPicksPerRepo
+	}
+	return keys
+}
+
+// bruteForce returns the highest total value of items that fit into the capacity. Must-pick items are picked first in
+// the order of their value while they fit into the total capacity, other items must fit into every capacity.
+func bruteForce(c *config.CapacityConfig, items []Item, maxTotal int) float64 {
+	used := map[string]float64{}
+	mustPicks := []Item{}
+	others := []Item{}
+	for _, item := range items {
+		if item.MustPick {
+			mustPicks = append(mustPicks, item)
+		} else if item.Value > 0 {
+			others = append(others, item)
+		}
+	}
+	sort.SliceStable(mustPicks, func(i, j int) bool { return mustPicks[i].Value > mustPicks[j].Value })
+	for _, item := range mustPicks {
+		if used["total"]+float64(item.Cost) > float64(maxTotal)+epsilon {
+			continue
+		}
+		for key := range constraintKeys(c, item, maxTotal) {
+			used[key] += float64(item.Cost)
+		}
+	}
+
+	best := float64(0)
+	for subset := 0; subset < 1<<len(others); subset++ {
+		subsetUsed := map[string]float64{}
+		value := float64(0)
+		fits := true
+		for i, item := range others {
+			if subset&(1<<i) == 0 {
+				continue
+			}
+			value += item.Value
+			for key, capacity := range constraintKeys(c, item, maxTotal) {
+				subsetUsed[key] += float64(item.Cost)
+				if used[key]+subsetUsed[key] > float64(capacity)+epsilon {
+					fits = false
+				}
+			}
+		}
+		if fits && value > best {
+			best = value
+		}
+	}
+	return best
+}
+
+// pickedValue returns the total value of picked items that are not must-pick and checks the picks fit into the capacity.
+func pickedValue(t *testing.T, c *config.CapacityConfig, items []Item, picked []bool, maxTotal int) float64 {
+	tracker := NewTracker(c)
+	// must-picks are recorded first, they are allowed over all capacities except the total one
+	for i, item := range items {
+		if picked[i] && item.MustPick {
+			tracker.Pick(item.Component, item.Repo, item.Cost)
+		}
+	}
+	value := float64(0)
+	for i, item := range items {
+		if !picked[i] || item.MustPick {
+			continue
+		}
+		if ok, reason := tracker.HasCapacity(item.Component, item.Repo, item.Cost); !ok {
+			t.Errorf("picked item %d does not fit: %s", i, reason)
+		}
+		tracker.Pick(item.Component, item.Repo, item.Cost)
+		value += item.Value
+	}
+	if Exceeds(tracker.Used(), 0, maxTotal) {
+		t.Errorf("picks take %g points over the total capacity %d", tracker.Used(), maxTotal)
+	}
+	return value
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.CapacityConfig
+		maxTotal int
+		mustPick bool
+	}{
+		{
+			name:     "total limit",
+			config:   config.CapacityConfig{MaximumDefaultPicksPerComponent: 100},
+			maxTotal: 5,
+		},
+		{
+			name:     "component limit",
+			config:   config.CapacityConfig{MaximumDefaultPicksPerComponent: 2},
+			maxTotal: 10,
+		},
+		{
+			name: "group limit",
+			config: config.CapacityConfig{
+				MaximumDefaultPicksPerComponent: 100,
+				Groups: []config.ComponentGroup{
+					{Name: "ab", Capacity: 3, Components: []string{"a", "b"}},
+					{Name: "c", Capacity: 1, Components: []string{"c"}},
+				},
+			},
+			maxTotal: 10,
+		},
+		{
+			name: "component limit within group",
+			config: config.CapacityConfig{
+				MaximumDefaultPicksPerComponent: 100,
+				Groups:                          []config.ComponentGroup{{Name: "ab", Capacity: 4, Components: []string{"a", "b"}, ComponentLimits: map[string]int{"a": 1}}},
+			},
+			maxTotal: 10,
+		},
+		{
+			name:     "repository limit",
+			config:   config.CapacityConfig{MaximumDefaultPicksPerComponent: 100, MaximumPicksPerRepo: 2},
+			maxTotal: 10,
+		},
+		{
+			name: "must-pick",
+			config: config.CapacityConfig{
+				MaximumDefaultPicksPerComponent: 2,
+				MaximumPicksPerRepo:             3,
+				Groups:                          []config.ComponentGroup{{Name: "ab", Capacity: 2, Components: []string{"a", "b"}}},
+			},
+			maxTotal: 6,
+			mustPick: true,
+		},
+	}
+	components := []string{"a", "b", "c", "d"}
+	repos := []string{"org/x", "org/y", "org/z"}
+	costs := []float32{0.5, 1, 1, 1.5, 2}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			random := rand.New(rand.NewSource(1))
+			for round := 0; round < 50; round++ {
+				items := make([]Item, 1+random.Intn(10))
+				for i := range items {
+					items[i] = Item{
+						Component: components[random.Intn(len(components))],
+						Repo:      repos[random.Intn(len(repos))],
+						Cost:      costs[random.Intn(len(costs))],
+						Value:     float64(random.Intn(30)) / 10,
+						MustPick:  test.mustPick && random.Intn(4) == 0,
+					}
+				}
+				picked, optimal := Solve(&test.config, items, test.maxTotal, NewTracker(&test.config))
+				if !optimal {
+					t.Fatalf("round %d: expected optimal selection", round)
+				}
+				value := pickedValue(t, &test.config, items, picked, test.maxTotal)
+				if expected := bruteForce(&test.config, items, test.maxTotal); value < expected-epsilon {
+					t.Errorf("round %d: expected total value %g, got %g for items %+v", round, expected, value, items)
+				}
+			}
+		})
+	}
+}
+
+func TestSolvePrefersTotalScore(t *testing.T) {
+	c := &config.CapacityConfig{MaximumDefaultPicksPerComponent: 10}
+	// single pull request with score 2.6 costing 3 points or three pull requests with score 1.5 costing 1 point each
+	items := []Item{
+		{Component: "a", Repo: "org/p", Cost: 3, Value: 2.601},
+		{Component: "a", Repo: "org/q", Cost: 1, Value: 1.501},
+		{Component: "a", Repo: "org/r", Cost: 1, Value: 1.501},
+		{Component: "a", Repo: "org/s", Cost: 1, Value: 1.501},
+	}
+	picked, _ := Solve(c, items, 3, NewTracker(c))
+	if fmt.Sprint(picked) != "[false true true true]" {
+		t.Errorf("expected the three cheaper pull requests to be picked, got %v", picked)
+	}
+}
+
+func TestSolveUsedCapacity(t *testing.T) {
+	c := &config.CapacityConfig{MaximumDefaultPicksPerComponent: 3, Groups: []config.ComponentGroup{{Name: "a", Capacity: 2, Components: []string{"a"}}}}
+	used := NewTracker(c)
+	used.Pick("a", "org/x", 1)
+	used.Pick("b", "org/x", 2)
+	items := []Item{
+		{Component: "a", Cost: 1, Value: 5},
+		{Component: "a", Cost: 1, Value: 4},
+		{Component: "b", Cost: 1, Value: 1},
+		{Component: "b", Cost: 1, Value: 1},
+	}
+	picked, _ := Solve(c, items, 10, used)
+	if fmt.Sprint(picked) != "[true false true false]" {
+		t.Errorf("expected one pick of a and b in the capacity left, got %v", picked)
+	}
+}
+
+func TestSolveNodeLimit(t *testing.T) {
+	defer func(limit int) { solverNodeLimit = limit }(solverNodeLimit)
+	solverNodeLimit = 10
+
+	c := &config.CapacityConfig{MaximumDefaultPicksPerComponent: 100}
+	items := []Item{}
+	for i := 0; i < 30; i++ {
+		items = append(items, Item{Component: "a", Repo: "org/x", Cost: float32(1 + i%3), Value: float64(1 + i%5)})
+	}
+	picked, optimal := Solve(c, items, 20, NewTracker(c))
+	if optimal {
+		t.Errorf("expected the search to be stopped by the node limit")
+	}
+	pickedValue(t, c, items, picked, 20)
+}
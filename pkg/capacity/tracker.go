@@ -0,0 +1,171 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+// epsilon tolerates rounding errors when adding up fractional costs
+const epsilon = 1e-4
+
+// Tracker counts picks and the capacity points they take per component, component group and repository and tells if
+// there is QE capacity for more picks. Components in a group share the group capacity, components outside of any group
+// get the default capacity each.
+type Tracker struct {
+	config *config.CapacityConfig
+
+	componentCandidates map[string]int
+	componentPicks      map[string]int
+	componentSkips      map[string]int
+	componentPoints     map[string]float32
+	groupPoints         map[string]float32
+	repoPoints          map[string]float32
+	totalPoints         float32
+}
+
+func NewTracker(c *config.CapacityConfig) *Tracker {
+	return &Tracker{
+		config:              c,
+		componentCandidates: map[string]int{},
+		componentPicks:      map[string]int{},
+		componentSkips:      map[string]int{},
+		componentPoints:     map[string]float32{},
+		groupPoints:         map[string]float32{},
+		repoPoints:          map[string]float32{},
+	}
+}
+
+// Exceeds returns true when adding the cost to already used points exceeds the capacity.
+func Exceeds(used, cost float32, capacity int) bool {
+	return used+cost > float32(capacity)+epsilon
+}
+
+// HasCapacity returns true when the component, its group and the repository (org/repo) can take another pick costing
+// given points, otherwise the reason why not.
+func (t *Tracker) HasCapacity(component, repo string, cost float32) (bool, string) {
+	if t.config.MaximumPicksPerRepo > 0 && Exceeds(t.repoPoints[repo], cost, t.config.MaximumPicksPerRepo) {
+		return false, fmt.Sprintf("maximum allowed capacity for repository %s is %d", repo, t.config.MaximumPicksPerRepo)
+	}
+	group := config.ComponentGroupFor(t.config, component)
+	if group == nil {
+		if Exceeds(t.componentPoints[component], cost, t.config.MaximumDefaultPicksPerComponent) {
+			return false, fmt.Sprintf("maximum allowed capacity for component %s is %d", component, t.config.MaximumDefaultPicksPerComponent)
+		}
+		return true, ""
+	}
+	if Exceeds(t.groupPoints[group.Name], cost, group.Capacity) {
+		return false, fmt.Sprintf("maximum allowed capacity for group %s (component %s) is %d", group.Name, component, group.Capacity)
+	}
+	if limited, limit := config.ComponentLimit(group, component); limited && Exceeds(t.componentPoints[component], cost, limit) {
+		return false, fmt.Sprintf("maximum allowed capacity for component %s in group %s is %d", component, group.Name, limit)
+	}
+	return true, ""
+}
+
+// Pick records the component pick taking given capacity points.
+func (t *Tracker) Pick(component, repo string, cost float32) {
+	t.componentCandidates[component]++
+	t.componentPicks[component]++
+	t.componentPoints[component] += cost
+	t.repoPoints[repo] += cost
+	t.totalPoints += cost
+	if group := config.ComponentGroupFor(t.config, component); group != nil {
+		t.groupPoints[group.Name] += cost
+	}
+}
+
+// Used returns the capacity points taken by all picks.
+func (t *Tracker) Used() float32 {
+	return t.totalPoints
+}
+
+// Copy returns independent copy of the tracker, eg. to try different selection without changing the tracker.
+func (t *Tracker) Copy() *Tracker {
+	result := NewTracker(t.config)
+	for _, m := range []struct{ from, to map[string]int }{
+		{t.componentCandidates, result.componentCandidates},
+		{t.componentPicks, result.componentPicks},
+		{t.componentSkips, result.componentSkips},
+	} {
+		for k, v := range m.from {
+			m.to[k] = v
+		}
+	}
+	for _, m := range []struct{ from, to map[string]float32 }{
+		{t.componentPoints, result.componentPoints},
+		{t.groupPoints, result.groupPoints},
+		{t.repoPoints, result.repoPoints},
+	} {
+		for k, v := range m.from {
+			m.to[k] = v
+		}
+	}
+	result.totalPoints = t.totalPoints
+	return result
+}
+
+// Skip records skipped candidate of the component.
+func (t *Tracker) Skip(component string) {
+	t.componentCandidates[component]++
+	t.componentSkips[component]++
+}
+
+// Metric describe candidates, picks and skips of a component or a group of components.
+type Metric struct {
+	Component string `header:"Component Name"`
+	Group     string `header:"Group"`
+	Total     int    `header:"Total"`
+	Picks     int    `header:"Picks"`
+	Skips     int    `header:"Skips"`
+	// Capacity is the used/available capacity points (only used points for components without own limit in a group)
+	Capacity string `header:"Capacity"`
+}
+
+// Metrics returns metrics of all groups followed by their components and metrics of components outside of any group.
+func (t *Tracker) Metrics() []Metric {
+	groups := map[string][]Metric{}
+	for component, total := range t.componentCandidates {
+		metric := Metric{
+			Component: component,
+			Total:     total,
+			Picks:     t.componentPicks[component],
+			Skips:     t.componentSkips[component],
+		}
+		group := config.ComponentGroupFor(t.config, component)
+		if group == nil {
+			metric.Capacity = fmt.Sprintf("%g/%d", t.componentPoints[component], t.config.MaximumDefaultPicksPerComponent)
+		} else {
+			metric.Group = group.Name
+			metric.Capacity = fmt.Sprintf("%g", t.componentPoints[component])
+			if limited, limit := config.ComponentLimit(group, component); limited {
+				metric.Capacity = fmt.Sprintf("%g/%d", t.componentPoints[component], limit)
+			}
+		}
+		groups[metric.Group] = append(groups[metric.Group], metric)
+	}
+
+	result := []Metric{}
+	for _, group := range t.config.Groups {
+		components := groups[group.Name]
+		sortMetrics(components)
+		groupMetric := Metric{Group: group.Name, Capacity: fmt.Sprintf("%g/%d", t.groupPoints[group.Name], group.Capacity)}
+		for _, m := range components {
+			groupMetric.Total += m.Total
+			groupMetric.Picks += m.Picks
+			groupMetric.Skips += m.Skips
+		}
+		result = append(result, groupMetric)
+		result = append(result, components...)
+	}
+	ungrouped := groups[""]
+	sortMetrics(ungrouped)
+	return append(result, ungrouped...)
+}
+
+func sortMetrics(metrics []Metric) {
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Component < metrics[j].Component
+	})
+}
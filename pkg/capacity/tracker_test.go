@@ -0,0 +1,159 @@
+package capacity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+func testCapacityConfig() *config.CapacityConfig {
+	return &config.CapacityConfig{
+		MaximumDefaultPicksPerComponent: 2,
+		MaximumPicksPerRepo:             3,
+		Groups: []config.ComponentGroup{
+			{Name: "Workloads", Capacity: 3, Components: []string{"oc", "kube-scheduler"}, ComponentLimits: map[string]int{"oc": 1}},
+		},
+	}
+}
+
+func TestHasCapacity(t *testing.T) {
+	type pick struct {
+		component, repo string
+		cost            float32
+	}
+	tests := []struct {
+		name      string
+		picks     []pick
+		component string
+		repo      string
+		cost      float32
+		expected  bool
+		reason    string
+	}{
+		{
+			name:      "empty group",
+			component: "kube-scheduler",
+			repo:      "openshift/kubernetes",
+			cost:      1,
+			expected:  true,
+		},
+		{
+			name:      "full group refuse any of its components",
+			picks:     []pick{{"kube-scheduler", "org/a", 2}, {"oc", "org/b", 1}},
+			component: "kube-scheduler",
+			repo:      "org/c",
+			cost:      1,
+			reason:    "maximum allowed capacity for group Workloads (component kube-scheduler) is 3",
+		},
+		{
+			name:      "component limit within the group",
+			picks:     []pick{{"oc", "org/a", 1}},
+			component: "oc",
+			repo:      "org/b",
+			cost:      1,
+			reason:    "maximum allowed capacity for component oc in group Workloads is 1",
+		},
+		{
+			name:      "group limit applies before component limit",
+			picks:     []pick{{"kube-scheduler", "org/a", 2.5}},
+			component: "oc",
+			repo:      "org/b",
+			cost:      1,
+			reason:    "maximum allowed capacity for group Workloads (component oc) is 3",
+		},
+		{
+			name:      "other components in group can use the rest of group capacity",
+			picks:     []pick{{"oc", "org/a", 1}},
+			component: "kube-scheduler",
+			repo:      "org/b",
+			cost:      2,
+			expected:  true,
+		},
+		{
+			name:      "component outside of groups get the default capacity",
+			picks:     []pick{{"etcd", "org/a", 1.5}},
+			component: "etcd",
+			repo:      "org/b",
+			cost:      0.5,
+			expected:  true,
+		},
+		{
+			name:      "default capacity exceeded",
+			picks:     []pick{{"etcd", "org/a", 1.5}},
+			component: "etcd",
+			repo:      "org/b",
+			cost:      1,
+			reason:    "maximum allowed capacity for component etcd is 2",
+		},
+		{
+			name:      "repository limit",
+			picks:     []pick{{"etcd", "org/a", 1}, {"networking", "org/a", 2}},
+			component: "storage",
+			repo:      "org/a",
+			cost:      1,
+			reason:    "maximum allowed capacity for repository org/a is 3",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := NewTracker(testCapacityConfig())
+			for _, p := range test.picks {
+				tracker.Pick(p.component, p.repo, p.cost)
+			}
+			ok, reason := tracker.HasCapacity(test.component, test.repo, test.cost)
+			if ok != test.expected || reason != test.reason {
+				t.Errorf("expected %v %q, got %v %q", test.expected, test.reason, ok, reason)
+			}
+		})
+	}
+}
+
+func TestTrackerCopy(t *testing.T) {
+	tracker := NewTracker(testCapacityConfig())
+	tracker.Pick("oc", "org/a", 1)
+	tracker.Skip("etcd")
+
+	copied := tracker.Copy()
+	copied.Pick("kube-scheduler", "org/a", 2)
+	copied.Pick("etcd", "org/b", 1)
+	copied.Skip("oc")
+
+	if tracker.Used() != 1 || copied.Used() != 4 {
+		t.Errorf("expected 1 point used by the original and 4 by the copy, got %g and %g", tracker.Used(), copied.Used())
+	}
+	if ok, _ := tracker.HasCapacity("kube-scheduler", "org/a", 2); !ok {
+		t.Errorf("expected picks in the copy not to take the original group capacity")
+	}
+	if ok, _ := copied.HasCapacity("kube-scheduler", "org/c", 1); ok {
+		t.Errorf("expected the copy to keep the picks of the original")
+	}
+	expected := []Metric{
+		{Group: "Workloads", Total: 1, Picks: 1, Capacity: "1/3"},
+		{Component: "oc", Group: "Workloads", Total: 1, Picks: 1, Capacity: "1/1"},
+		{Component: "etcd", Total: 1, Skips: 1, Capacity: "0/2"},
+	}
+	if metrics := tracker.Metrics(); !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("expected original metrics %+v, got %+v", expected, metrics)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tracker := NewTracker(testCapacityConfig())
+	tracker.Pick("oc", "org/a", 1)
+	tracker.Pick("kube-scheduler", "org/a", 1.5)
+	tracker.Skip("kube-scheduler")
+	tracker.Pick("networking", "org/b", 1)
+	tracker.Skip("etcd")
+
+	expected := []Metric{
+		{Group: "Workloads", Total: 3, Picks: 2, Skips: 1, Capacity: "2.5/3"},
+		{Component: "kube-scheduler", Group: "Workloads", Total: 2, Picks: 1, Skips: 1, Capacity: "1.5"},
+		{Component: "oc", Group: "Workloads", Total: 1, Picks: 1, Capacity: "1/1"},
+		{Component: "etcd", Total: 1, Skips: 1, Capacity: "0/2"},
+		{Component: "networking", Total: 1, Picks: 1, Capacity: "1/2"},
+	}
+	if metrics := tracker.Metrics(); !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("expected %+v, got %+v", expected, metrics)
+	}
+}
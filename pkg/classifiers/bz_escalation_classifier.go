@@ -0,0 +1,29 @@
+package classifiers
+
+import (
+	"github.com/openshift/patchmanager/pkg/config"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// EscalationClassifier classify pull request based on customer impact of the bug.
+// Escalated bugs get fixed score and every linked customer case adds score up to configured maximum number of cases.
+type EscalationClassifier struct {
+	Config *config.EscalationClassifierConfig
+}
+
+func (e *EscalationClassifier) Name() string {
+	return "escalation"
+}
+
+func (e *EscalationClassifier) Score(pullRequest *github.PullRequest) float32 {
+	score := float32(0)
+	if pullRequest.Bug().Escalated {
+		score += e.Config.Escalated
+	}
+	cases := len(pullRequest.Bug().CustomerCases)
+	if e.Config.MaxCases > 0 && cases > e.Config.MaxCases {
+		cases = e.Config.MaxCases
+	}
+	return score + float32(cases)*e.Config.PerCase
+}
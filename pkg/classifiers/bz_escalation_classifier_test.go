@@ -0,0 +1,59 @@
+package classifiers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestEscalationClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    config.EscalationClassifierConfig
+		escalated bool
+		cases     []string
+		expected  float32
+	}{
+		{
+			name:   "no customer impact",
+			config: config.EscalationClassifierConfig{Escalated: 1, PerCase: 0.1, MaxCases: 3},
+		},
+		{
+			name:      "escalated",
+			config:    config.EscalationClassifierConfig{Escalated: 1, PerCase: 0.1, MaxCases: 3},
+			escalated: true,
+			expected:  1,
+		},
+		{
+			name:     "customer cases",
+			config:   config.EscalationClassifierConfig{Escalated: 1, PerCase: 0.1, MaxCases: 3},
+			cases:    []string{"01", "02"},
+			expected: 0.2,
+		},
+		{
+			name:      "customer cases over maximum",
+			config:    config.EscalationClassifierConfig{Escalated: 1, PerCase: 0.1, MaxCases: 3},
+			escalated: true,
+			cases:     []string{"01", "02", "03", "04", "05"},
+			expected:  1.3,
+		},
+		{
+			name:     "no maximum",
+			config:   config.EscalationClassifierConfig{PerCase: 0.1},
+			cases:    []string{"01", "02", "03", "04", "05"},
+			expected: 0.5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			classifier := &EscalationClassifier{Config: &test.config}
+			pullRequest := fake.NewPullRequest(fake.PullRequest{Bug: &tracker.Issue{ID: "1", Escalated: test.escalated, CustomerCases: test.cases}})
+			if score := classifier.Score(pullRequest); math.Abs(float64(score-test.expected)) > 1e-6 {
+				t.Errorf("expected score %g, got %g", test.expected, score)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package classifiers
+
+import (
+	"github.com/openshift/patchmanager/pkg/config"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// FlagsClassifier classify pull request based on bugzilla flags (eg. "blocker+").
+// Score of all flags set on the bug is added up.
+type FlagsClassifier struct {
+	Config *config.FlagsClassifierConfig
+}
+
+func (f *FlagsClassifier) Name() string {
+	return "flags"
+}
+
+func (f *FlagsClassifier) Score(pullRequest *github.PullRequest) float32 {
+	score := float32(0)
+	for _, flag := range pullRequest.Bug().Flags {
+		score += (*f.Config)[flag]
+	}
+	return score
+}
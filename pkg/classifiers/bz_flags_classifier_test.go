@@ -0,0 +1,29 @@
+package classifiers
+
+import (
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestFlagsClassifier(t *testing.T) {
+	classifier := &FlagsClassifier{Config: &config.FlagsClassifierConfig{"blocker+": 1, "blocker-": -0.5, "requires_doc_text-": 0.25}}
+	tests := []struct {
+		flags    []string
+		expected float32
+	}{
+		{},
+		{flags: []string{"blocker+"}, expected: 1},
+		{flags: []string{"blocker?", "backport+"}},
+		{flags: []string{"blocker-", "requires_doc_text-"}, expected: -0.25},
+		{flags: []string{"blocker+", "requires_doc_text-", "backport+"}, expected: 1.25},
+	}
+	for _, test := range tests {
+		pullRequest := fake.NewPullRequest(fake.PullRequest{Bug: &tracker.Issue{ID: "1", Flags: test.flags}})
+		if score := classifier.Score(pullRequest); score != test.expected {
+			t.Errorf("%v: expected score %g, got %g", test.flags, test.expected, score)
+		}
+	}
+}
@@ -10,6 +10,10 @@ type KeywordsClassifier struct {
 	Config *config.KeywordsClassifierConfig
 }
 
+func (f *KeywordsClassifier) Name() string {
+	return "keywords"
+}
+
 func (f *KeywordsClassifier) Score(pullRequest *github.PullRequest) float32 {
 	highestScore := float32(0)
 	for keyword, score := range *f.Config {
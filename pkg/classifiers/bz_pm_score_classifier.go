@@ -13,6 +13,10 @@ type ProductManagementScoreClassifier struct {
 	Config *config.PMScoreClassifierConfig
 }
 
+func (p *ProductManagementScoreClassifier) Name() string {
+	return "pmScore"
+}
+
 func (p *ProductManagementScoreClassifier) Score(pullRequest *github.PullRequest) float32 {
 	pmScore, err := strconv.Atoi(pullRequest.Bug().PMScore)
 	if err != nil {
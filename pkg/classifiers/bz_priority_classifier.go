@@ -0,0 +1,27 @@
+package classifiers
+
+import (
+	"strings"
+
+	"github.com/openshift/patchmanager/pkg/config"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// PriorityClassifier classify pull request based on the bugzilla priority.
+// Component teams set the priority to express the z-stream urgency of the fix.
+type PriorityClassifier struct {
+	Config *config.PriorityClassifierConfig
+}
+
+func (p *PriorityClassifier) Name() string {
+	return "priority"
+}
+
+func (p *PriorityClassifier) Score(pullRequest *github.PullRequest) float32 {
+	score, ok := (*p.Config)[strings.ToLower(pullRequest.Bug().Priority)]
+	if !ok {
+		return 0
+	}
+	return score
+}
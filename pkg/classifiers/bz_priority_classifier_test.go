@@ -0,0 +1,30 @@
+package classifiers
+
+import (
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestPriorityClassifier(t *testing.T) {
+	classifier := &PriorityClassifier{Config: &config.PriorityClassifierConfig{"urgent": 1, "high": 0.5, "low": -0.5}}
+	tests := []struct {
+		priority string
+		expected float32
+	}{
+		{priority: "urgent", expected: 1},
+		{priority: "High", expected: 0.5},
+		{priority: "low", expected: -0.5},
+		{priority: "medium"},
+		{priority: "unspecified"},
+		{},
+	}
+	for _, test := range tests {
+		pullRequest := fake.NewPullRequest(fake.PullRequest{Bug: &tracker.Issue{ID: "1", Priority: test.priority}})
+		if score := classifier.Score(pullRequest); score != test.expected {
+			t.Errorf("%q: expected score %g, got %g", test.priority, test.expected, score)
+		}
+	}
+}
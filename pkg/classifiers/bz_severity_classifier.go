@@ -15,6 +15,10 @@ type SeverityClassifier struct {
 	Config *config.SeverityClassifierConfig
 }
 
+func (s *SeverityClassifier) Name() string {
+	return "severity"
+}
+
 func (s *SeverityClassifier) Score(pullRequest *github.PullRequest) float32 {
 	score, ok := (*s.Config)[strings.ToLower(pullRequest.Bug().Severity)]
 	if !ok {
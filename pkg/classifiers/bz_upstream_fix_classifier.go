@@ -0,0 +1,27 @@
+package classifiers
+
+import (
+	"github.com/openshift/patchmanager/pkg/config"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// UpstreamFixClassifier penalise backports which parent bug (the bug in the next release it was cloned from) was not verified yet.
+type UpstreamFixClassifier struct {
+	Config *config.UpstreamFixClassifierConfig
+}
+
+func (u *UpstreamFixClassifier) Name() string {
+	return "upstreamFix"
+}
+
+func (u *UpstreamFixClassifier) Score(pullRequest *github.PullRequest) float32 {
+	if u.Config.NotVerified == 0 {
+		return 0
+	}
+	// parent bugs that can't be fetched are penalised as well, the fix can't be confirmed
+	if id, _, _ := pullRequest.UnverifiedParentBug(u.Config.VerifiedStatuses); len(id) > 0 {
+		return u.Config.NotVerified
+	}
+	return 0
+}
@@ -1,27 +1,99 @@
 package classifiers
 
 import (
+	"math"
+
 	"github.com/openshift/patchmanager/pkg/github"
 )
 
 // Classifier interface define Score function that every classifier must implement
 type Classifier interface {
+	// Name is used to identify the classifier contribution in the score breakdown
+	Name() string
 	Score(*github.PullRequest) float32
 }
 
+// BreakdownClassifier is implemented by classifiers that combine multiple classifiers and are able to tell how much each
+// of them contributed to the total score.
+type BreakdownClassifier interface {
+	Classifier
+	// ScoreBreakdown returns the total score and the score contributions indexed by classifier names
+	ScoreBreakdown(*github.PullRequest) (float32, map[string]float32)
+}
+
+const (
+	// CombineSum adds weighted scores of all classifiers.
+	CombineSum = "sum"
+	// CombineMax takes the highest weighted score of all classifiers.
+	CombineMax = "max"
+	// CombineProduct multiplies (1 + score) ^ weight of all classifiers and subtract 1, so classifiers with zero score
+	// do not change the total score.
+	CombineProduct = "product"
+)
+
 // MultiClassifier groups multiple classifier together and perform synchronous classifications
 type MultiClassifier struct {
 	classifiers []Classifier
+	// weights multiply the classifier scores, nil means weight 1 for all classifiers
+	weights []float32
+	combine string
+}
+
+var _ BreakdownClassifier = &MultiClassifier{}
+
+func (m *MultiClassifier) Name() string {
+	return "multi"
+}
+
+// Classifiers returns all classifiers this classifier combine.
+func (m *MultiClassifier) Classifiers() []Classifier {
+	return m.classifiers
 }
 
 func (m *MultiClassifier) Score(pullRequest *github.PullRequest) float32 {
-	score := float32(0)
+	score, _ := m.ScoreBreakdown(pullRequest)
+	return score
+}
+
+// ScoreBreakdown returns the combined score and the weighted score of each classifier.
+func (m *MultiClassifier) ScoreBreakdown(pullRequest *github.PullRequest) (float32, map[string]float32) {
+	breakdown := map[string]float32{}
+	scores := make([]float32, len(m.classifiers))
+	weights := make([]float32, len(m.classifiers))
 	for i := range m.classifiers {
-		score += m.classifiers[i].Score(pullRequest)
+		weights[i] = 1
+		if m.weights != nil {
+			weights[i] = m.weights[i]
+		}
+		scores[i] = m.classifiers[i].Score(pullRequest)
+		breakdown[m.classifiers[i].Name()] += scores[i] * weights[i]
+	}
+
+	switch m.combine {
+	case CombineMax:
+		score := float32(0)
+		for i := range scores {
+			if i == 0 || scores[i]*weights[i] > score {
+				score = scores[i] * weights[i]
+			}
+		}
+		return score, breakdown
+	case CombineProduct:
+		product := float64(1)
+		for i := range scores {
+			// classifier score of -1 or lower zeroes the product
+			product *= math.Pow(math.Max(0, 1+float64(scores[i])), float64(weights[i]))
+		}
+		return float32(product - 1), breakdown
+	default:
+		score := float32(0)
+		for i := range scores {
+			score += scores[i] * weights[i]
+		}
+		return score, breakdown
 	}
-	return score
 }
 
 func NewMultiClassifier(classifiers ...Classifier) Classifier {
-	return &MultiClassifier{classifiers: classifiers}
+	return &MultiClassifier{classifiers: classifiers, combine: CombineSum}
 }
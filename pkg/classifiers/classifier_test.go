@@ -0,0 +1,191 @@
+package classifiers
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// fixedClassifier gives every pull request the same score.
+type fixedClassifier struct {
+	name  string
+	score float32
+}
+
+func (f *fixedClassifier) Name() string {
+	return f.name
+}
+
+func (f *fixedClassifier) Score(*github.PullRequest) float32 {
+	return f.score
+}
+
+func TestScoreBreakdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		combine   string
+		scores    []float32
+		weights   []float32
+		expected  float32
+		breakdown map[string]float32
+	}{
+		{
+			name:      "sum",
+			combine:   CombineSum,
+			scores:    []float32{0.5, -0.2, 0.3},
+			weights:   []float32{2, 1, 0.5},
+			expected:  0.95,
+			breakdown: map[string]float32{"a": 1, "b": -0.2, "c": 0.15},
+		},
+		{
+			name:      "sum with default weights",
+			combine:   CombineSum,
+			scores:    []float32{0.5, -0.2, 0.3},
+			expected:  0.6,
+			breakdown: map[string]float32{"a": 0.5, "b": -0.2, "c": 0.3},
+		},
+		{
+			name:      "max",
+			combine:   CombineMax,
+			scores:    []float32{0.5, -0.2, 0.3},
+			weights:   []float32{1, 1, 2},
+			expected:  0.6,
+			breakdown: map[string]float32{"a": 0.5, "b": -0.2, "c": 0.6},
+		},
+		{
+			name:      "max of negative scores",
+			combine:   CombineMax,
+			scores:    []float32{-0.5, -0.2},
+			expected:  -0.2,
+			breakdown: map[string]float32{"a": -0.5, "b": -0.2},
+		},
+		{
+			name:    "product",
+			combine: CombineProduct,
+			scores:  []float32{0.5, 0, 0.2},
+			weights: []float32{2, 1, 1},
+			// 1.5^2 * 1 * 1.2 - 1
+			expected:  1.7,
+			breakdown: map[string]float32{"a": 1, "b": 0, "c": 0.2},
+		},
+		{
+			name:    "product with negative score",
+			combine: CombineProduct,
+			scores:  []float32{0.5, -0.5},
+			weights: []float32{2, 1},
+			// 1.5^2 * 0.5 - 1
+			expected:  0.125,
+			breakdown: map[string]float32{"a": 1, "b": -0.5},
+		},
+		{
+			name:      "product with score lower than -1",
+			combine:   CombineProduct,
+			scores:    []float32{0.5, -1.5},
+			expected:  -1,
+			breakdown: map[string]float32{"a": 0.5, "b": -1.5},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &MultiClassifier{combine: test.combine, weights: test.weights}
+			for i, score := range test.scores {
+				m.classifiers = append(m.classifiers, &fixedClassifier{name: string(rune('a' + i)), score: score})
+			}
+			score, breakdown := m.ScoreBreakdown(nil)
+			if math.Abs(float64(score-test.expected)) > 1e-6 {
+				t.Errorf("expected score %g, got %g", test.expected, score)
+			}
+			for name, expected := range test.breakdown {
+				if math.Abs(float64(breakdown[name]-expected)) > 1e-6 {
+					t.Errorf("expected %s score %g, got %g", name, expected, breakdown[name])
+				}
+			}
+			if len(breakdown) != len(test.breakdown) {
+				t.Errorf("expected breakdown %v, got %v", test.breakdown, breakdown)
+			}
+		})
+	}
+}
+
+func TestNewClassifierFromConfig(t *testing.T) {
+	weight := float32(2)
+	tests := []struct {
+		name    string
+		config  config.ClassifierConfig
+		names   []string
+		weights []float32
+		combine string
+		err     string
+	}{
+		{
+			name:    "all classifiers enabled by default",
+			names:   registryOrder,
+			combine: CombineSum,
+		},
+		{
+			name: "enabled classifiers with weights in the configured order",
+			config: config.ClassifierConfig{
+				Enabled: []config.EnabledClassifier{{Name: "keywords"}, {Name: "severity", Weight: &weight}},
+				Combine: CombineProduct,
+			},
+			names:   []string{"keywords", "severity"},
+			weights: []float32{1, 2},
+			combine: CombineProduct,
+		},
+		{
+			name:   "unknown classifier",
+			config: config.ClassifierConfig{Enabled: []config.EnabledClassifier{{Name: "bugAge"}}},
+			err:    `unknown classifier "bugAge" (registered: component, escalation, expressions, flags, keywords, pmScore, priority, risk, severity, upstreamFix)`,
+		},
+		{
+			name:   "classifier enabled twice",
+			config: config.ClassifierConfig{Enabled: []config.EnabledClassifier{{Name: "severity"}, {Name: "severity"}}},
+			err:    `classifier "severity" is enabled more than once`,
+		},
+		{
+			name:   "unknown combine",
+			config: config.ClassifierConfig{Combine: "average"},
+			err:    `unknown classifiers combination "average" (supported: sum, max, product)`,
+		},
+		{
+			name: "invalid classifier config",
+			config: config.ClassifierConfig{
+				Enabled:     []config.EnabledClassifier{{Name: "expressions"}},
+				Expressions: config.ExpressionClassifierConfig{{Expression: "severity ==", Score: 1}},
+			},
+			err: `unable to create classifier "expressions"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewClassifierFromConfig(&test.config, time.Now())
+			if len(test.err) > 0 {
+				if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, c := range m.Classifiers() {
+				names = append(names, c.Name())
+			}
+			if !reflect.DeepEqual(names, test.names) {
+				t.Errorf("expected classifiers %v, got %v", test.names, names)
+			}
+			if test.weights != nil && !reflect.DeepEqual(m.weights, test.weights) {
+				t.Errorf("expected weights %v, got %v", test.weights, m.weights)
+			}
+			if m.combine != test.combine {
+				t.Errorf("expected combine %q, got %q", test.combine, m.combine)
+			}
+		})
+	}
+}
@@ -14,7 +14,14 @@ type ComponentClassifier struct {
 	Config *config.ComponentClassifierConfig
 }
 
+func (c *ComponentClassifier) Name() string {
+	return "component"
+}
+
 func (c *ComponentClassifier) Score(pullRequest *github.PullRequest) float32 {
+	if len(pullRequest.Bug().Component) == 0 {
+		return 0
+	}
 	score, ok := (*c.Config)[strings.ToLower(pullRequest.Bug().Component[0])]
 	if !ok {
 		return 0
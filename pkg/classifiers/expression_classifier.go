@@ -0,0 +1,53 @@
+package classifiers
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/expression"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// ExpressionClassifier classify pull request using CEL expressions from the config, every expression that evaluates to true
+// adds its score.
+type ExpressionClassifier struct {
+	expressions []*expression.Expression
+	scores      []float32
+	// now is the time of the run the expressions are evaluated at
+	now time.Time
+}
+
+// NewExpressionClassifier compiles all configured expressions evaluated at given time of the run.
+func NewExpressionClassifier(c *config.ExpressionClassifierConfig, now time.Time) (*ExpressionClassifier, error) {
+	classifier := &ExpressionClassifier{now: now}
+	for _, e := range *c {
+		compiled, err := expression.Compile(e.Expression)
+		if err != nil {
+			return nil, err
+		}
+		classifier.expressions = append(classifier.expressions, compiled)
+		classifier.scores = append(classifier.scores, e.Score)
+	}
+	return classifier, nil
+}
+
+func (e *ExpressionClassifier) Name() string {
+	return "expressions"
+}
+
+func (e *ExpressionClassifier) Score(pullRequest *github.PullRequest) float32 {
+	score := float32(0)
+	for i := range e.expressions {
+		match, err := e.expressions[i].Evaluate(pullRequest, e.now)
+		if err != nil {
+			klog.Warningf("WARNING: %s: %v", pullRequest.Issue.GetHTMLURL(), err)
+			continue
+		}
+		if match {
+			score += e.scores[i]
+		}
+	}
+	return score
+}
@@ -0,0 +1,43 @@
+package classifiers
+
+import (
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestExpressionClassifier(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-30 * 24 * time.Hour)
+	pullRequest := fake.NewPullRequest(fake.PullRequest{
+		Issue: &githubapi.Issue{HTMLURL: githubapi.String("https://github.com/openshift/installer/pull/1"), CreatedAt: &created},
+		Bug:   &tracker.Issue{ID: "1", Severity: "urgent", Whiteboard: "UpgradeBlocker"},
+	})
+	classifier, err := NewExpressionClassifier(&config.ExpressionClassifierConfig{
+		{Expression: `whiteboard.contains("UpgradeBlocker")`, Score: 1},
+		{Expression: `severity == "urgent"`, Score: 0.5},
+		{Expression: `repo == "openshift/kubernetes"`, Score: 2},
+		{Expression: `ageDays >= 30`, Score: -0.3},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score := classifier.Score(pullRequest); score != 1.2 {
+		t.Errorf("expected score 1.2, got %g", score)
+	}
+
+	// the same pull request scored at the time of the recorded run
+	classifier, _ = NewExpressionClassifier(&config.ExpressionClassifierConfig{{Expression: `ageDays >= 30`, Score: -0.3}}, now.Add(-time.Hour))
+	if score := classifier.Score(pullRequest); score != 0 {
+		t.Errorf("expected no score before the pull request is 30 days old, got %g", score)
+	}
+
+	if _, err := NewExpressionClassifier(&config.ExpressionClassifierConfig{{Expression: `severity`, Score: 1}}, now); err == nil {
+		t.Errorf("expected error for expression not returning bool")
+	}
+}
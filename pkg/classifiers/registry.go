@@ -0,0 +1,113 @@
+package classifiers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/patchmanager/pkg/config"
+)
+
+// Factory creates classifier from the classifiers config, now is the time of the run time dependent classifiers are
+// evaluated at (the recorded time when replaying).
+type Factory func(c *config.ClassifierConfig, now time.Time) (Classifier, error)
+
+var (
+	registry = map[string]Factory{}
+	// registryOrder keeps the order classifiers were registered in, it is the order of the score breakdown
+	registryOrder []string
+)
+
+func init() {
+	Register("severity", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &SeverityClassifier{Config: &c.Severities}, nil
+	})
+	Register("priority", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &PriorityClassifier{Config: &c.Priorities}, nil
+	})
+	Register("component", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &ComponentClassifier{Config: &c.ComponentClassifier}, nil
+	})
+	Register("keywords", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &KeywordsClassifier{Config: &c.KeywordsClassifier}, nil
+	})
+	Register("pmScore", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &ProductManagementScoreClassifier{Config: &c.PMScores}, nil
+	})
+	Register("escalation", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &EscalationClassifier{Config: &c.Escalation}, nil
+	})
+	Register("flags", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &FlagsClassifier{Config: &c.Flags}, nil
+	})
+	Register("upstreamFix", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &UpstreamFixClassifier{Config: &c.UpstreamFix}, nil
+	})
+	Register("risk", func(c *config.ClassifierConfig, _ time.Time) (Classifier, error) {
+		return &RiskClassifier{Config: &c.Risk}, nil
+	})
+	Register("expressions", func(c *config.ClassifierConfig, now time.Time) (Classifier, error) {
+		return NewExpressionClassifier(&c.Expressions, now)
+	})
+}
+
+// Register makes the classifier available to be enabled in the config under given name.
+// The name should match the classifier Name().
+func Register(name string, factory Factory) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("classifier %q is already registered", name))
+	}
+	registry[name] = factory
+	registryOrder = append(registryOrder, name)
+}
+
+// Registered returns sorted names of all registered classifiers.
+func Registered() []string {
+	names := append([]string{}, registryOrder...)
+	sort.Strings(names)
+	return names
+}
+
+// NewClassifierFromConfig returns classifier combining all classifiers enabled in the classifiers config evaluated at
+// given time of the run.
+func NewClassifierFromConfig(c *config.ClassifierConfig, now time.Time) (*MultiClassifier, error) {
+	enabled := c.Enabled
+	if len(enabled) == 0 {
+		for _, name := range registryOrder {
+			enabled = append(enabled, config.EnabledClassifier{Name: name})
+		}
+	}
+
+	multi := &MultiClassifier{combine: c.Combine}
+	switch c.Combine {
+	case "":
+		multi.combine = CombineSum
+	case CombineSum, CombineMax, CombineProduct:
+	default:
+		return nil, fmt.Errorf("unknown classifiers combination %q (supported: %s, %s, %s)", c.Combine, CombineSum, CombineMax, CombineProduct)
+	}
+
+	seen := map[string]bool{}
+	for _, e := range enabled {
+		factory, ok := registry[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown classifier %q (registered: %s)", e.Name, strings.Join(Registered(), ", "))
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("classifier %q is enabled more than once", e.Name)
+		}
+		seen[e.Name] = true
+		classifier, err := factory(c, now)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create classifier %q: %v", e.Name, err)
+		}
+		weight := float32(1)
+		if e.Weight != nil {
+			weight = *e.Weight
+		}
+		multi.classifiers = append(multi.classifiers, classifier)
+		multi.weights = append(multi.weights, weight)
+	}
+	return multi, nil
+}
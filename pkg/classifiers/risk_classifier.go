@@ -0,0 +1,64 @@
+package classifiers
+
+import (
+	"path"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// RiskClassifier classify pull request based on the size of the code change and the paths it touches.
+// The configured scores are expected to be negative, so risky backports drop down the list.
+type RiskClassifier struct {
+	Config *config.RiskClassifierConfig
+}
+
+func (r *RiskClassifier) Name() string {
+	return "risk"
+}
+
+func (r *RiskClassifier) Score(pullRequest *github.PullRequest) float32 {
+	score := float32(0)
+	if r.Config.PerHundredLines != 0 || r.Config.PerFile != 0 {
+		details, err := pullRequest.Details()
+		if err != nil {
+			klog.Warningf("WARNING: Unable to get details of %s: %v", pullRequest.Issue.GetHTMLURL(), err)
+			return 0
+		}
+		score += float32(details.GetAdditions()+details.GetDeletions()) / 100 * r.Config.PerHundredLines
+		score += float32(details.GetChangedFiles()) * r.Config.PerFile
+	}
+	if len(r.Config.HighRiskPaths) > 0 {
+		files, err := pullRequest.Files()
+		if err != nil {
+			klog.Warningf("WARNING: Unable to list files of %s: %v", pullRequest.Issue.GetHTMLURL(), err)
+			return 0
+		}
+		for _, p := range r.Config.HighRiskPaths {
+			for _, f := range files {
+				if matchRiskPath(p.Glob, f.GetFilename()) {
+					score += p.Score
+					break
+				}
+			}
+		}
+	}
+	if r.Config.MinScore != 0 && score < r.Config.MinScore {
+		return r.Config.MinScore
+	}
+	return score
+}
+
+func matchRiskPath(glob, fileName string) bool {
+	if strings.HasSuffix(glob, "/") {
+		return strings.HasPrefix(fileName, glob) || strings.Contains(fileName, "/"+glob)
+	}
+	if ok, _ := path.Match(glob, fileName); ok {
+		return true
+	}
+	ok, _ := path.Match(glob, path.Base(fileName))
+	return ok
+}
@@ -0,0 +1,115 @@
+package classifiers
+
+import (
+	"math"
+	"testing"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestMatchRiskPath(t *testing.T) {
+	tests := []struct {
+		glob     string
+		fileName string
+		expected bool
+	}{
+		{glob: "vendor/", fileName: "vendor/k8s.io/api/types.go", expected: true},
+		{glob: "vendor/", fileName: "staging/src/vendor/types.go", expected: true},
+		{glob: "vendor/", fileName: "pkg/myvendor/types.go"},
+		{glob: "vendor/", fileName: "vendor.go"},
+		{glob: "*.crd.yaml", fileName: "manifests/0000_config.crd.yaml", expected: true},
+		{glob: "*.crd.yaml", fileName: "manifests/deployment.yaml"},
+		{glob: "manifests/*.yaml", fileName: "manifests/deployment.yaml", expected: true},
+		{glob: "manifests/*.yaml", fileName: "install/manifests/deployment.yaml"},
+		{glob: "go.mod", fileName: "go.mod", expected: true},
+	}
+	for _, test := range tests {
+		if result := matchRiskPath(test.glob, test.fileName); result != test.expected {
+			t.Errorf("%q matching %q: expected %v, got %v", test.glob, test.fileName, test.expected, result)
+		}
+	}
+}
+
+func TestRiskClassifier(t *testing.T) {
+	const prURL = "https://github.com/openshift/origin/pull/1"
+	details := &githubapi.PullRequest{Additions: githubapi.Int(250), Deletions: githubapi.Int(50), ChangedFiles: githubapi.Int(4)}
+	files := []*githubapi.CommitFile{
+		{Filename: githubapi.String("vendor/k8s.io/api/types.go")},
+		{Filename: githubapi.String("vendor/k8s.io/api/register.go")},
+		{Filename: githubapi.String("manifests/0000_config.crd.yaml")},
+		{Filename: githubapi.String("pkg/operator/sync.go")},
+	}
+	tests := []struct {
+		name     string
+		config   config.RiskClassifierConfig
+		expected float32
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:     "per hundred lines",
+			config:   config.RiskClassifierConfig{PerHundredLines: -0.1},
+			expected: -0.3,
+		},
+		{
+			name:     "per file",
+			config:   config.RiskClassifierConfig{PerFile: -0.05},
+			expected: -0.2,
+		},
+		{
+			name: "high risk paths count once per path",
+			config: config.RiskClassifierConfig{HighRiskPaths: []config.RiskPath{
+				{Glob: "vendor/", Score: -0.5},
+				{Glob: "*.crd.yaml", Score: -0.3},
+				{Glob: "docs/", Score: -1},
+			}},
+			expected: -0.8,
+		},
+		{
+			name: "all terms",
+			config: config.RiskClassifierConfig{
+				PerHundredLines: -0.1,
+				PerFile:         -0.05,
+				HighRiskPaths:   []config.RiskPath{{Glob: "vendor/", Score: -0.5}},
+			},
+			expected: -1,
+		},
+		{
+			name: "min score clamp",
+			config: config.RiskClassifierConfig{
+				PerHundredLines: -0.1,
+				PerFile:         -0.05,
+				HighRiskPaths:   []config.RiskPath{{Glob: "vendor/", Score: -0.5}},
+				MinScore:        -0.6,
+			},
+			expected: -0.6,
+		},
+		{
+			name: "min score above the score",
+			config: config.RiskClassifierConfig{
+				PerHundredLines: -0.1,
+				MinScore:        -0.6,
+			},
+			expected: -0.3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pullRequest := fake.NewPullRequest(fake.PullRequest{
+				Issue:        &githubapi.Issue{HTMLURL: githubapi.String(prURL)},
+				Bug:          &tracker.Issue{ID: "1"},
+				RelatedPulls: map[string]*githubapi.PullRequest{prURL: details},
+				Files:        files,
+			})
+			classifier := &RiskClassifier{Config: &test.config}
+			if score := classifier.Score(pullRequest); math.Abs(float64(score-test.expected)) > 1e-6 {
+				t.Errorf("expected score %g, got %g", test.expected, score)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/openshift/patchmanager/pkg/config"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/openshift/patchmanager/pkg/github"
 
+	"github.com/openshift/patchmanager/pkg/api"
 	v1 "github.com/openshift/patchmanager/pkg/api/v1"
 	"gopkg.in/yaml.v2"
 
@@ -134,6 +136,18 @@ func skippedPRs(prs v1.ApprovedCandidateList) []v1.ApprovedCandidate {
 	return result
 }
 
+// erroredPRs returns candidates which bug could not be fetched, the patch manager must decide about them manually.
+func erroredPRs(prs v1.ApprovedCandidateList) []v1.ApprovedCandidate {
+	result := []v1.ApprovedCandidate{}
+	for i := range prs.Items {
+		if prs.Items[i].PullRequest.Decision != "error" {
+			continue
+		}
+		result = append(result, prs.Items[i])
+	}
+	return result
+}
+
 func (r *approveOptions) Run(ctx context.Context) error {
 	content, err := ioutil.ReadFile(r.inFile)
 	if err != nil {
@@ -146,6 +160,15 @@ func (r *approveOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	if errored := erroredPRs(prs); len(errored) > 0 {
+		urls := []string{}
+		for _, pr := range errored {
+			urls = append(urls, pr.PullRequest.URL)
+		}
+		return fmt.Errorf("%d pull requests still have the error decision, change it to pick or skip in %s first:\n%s",
+			len(errored), r.inFile, strings.Join(urls, "\n"))
+	}
+
 	approved := approvedPRs(prs)
 	skipped := skippedPRs(prs)
 	if len(approved) == 0 && len(skipped) == 0 {
@@ -187,15 +210,19 @@ func (r *approveOptions) Run(ctx context.Context) error {
 		if len(r.skipComment) > 0 {
 			skipCommentMsg = fmt.Sprintf("\n*%s*\n", r.skipComment)
 		}
+		scoreBreakdownMsg := ""
+		if breakdown := api.FormatScoreBreakdown(pr.PullRequest.ScoreBreakdown); len(breakdown) > 0 {
+			scoreBreakdownMsg = fmt.Sprintf(" (%s)", breakdown)
+		}
 		if err := approver.Comment(ctx, pr.PullRequest.URL, fmt.Sprintf(`
 [patch-manager] :hourglass: This pull request was not picked by the patch manager for the current z-stream window and have to wait for the next window%s.
 %s
-* Score: *%0.2f*
+* Score: *%0.2f*%s
 * Reason: *%s*
 
 **NOTE**: This message was automatically generated, if you have questions please ask on #forum-release
 `,
-			mergeWindowMsg, skipCommentMsg, pr.PullRequest.Score, pr.PullRequest.DecisionReason)); err != nil {
+			mergeWindowMsg, skipCommentMsg, pr.PullRequest.Score, scoreBreakdownMsg, pr.PullRequest.DecisionReason)); err != nil {
 			klog.Errorf("Failed to comment on pull request %q: %v", pr.PullRequest.URL, err)
 		}
 		fmt.Fprintf(os.Stdout, "-> Commenting on skipping %s ...\n", pr.PullRequest.URL)
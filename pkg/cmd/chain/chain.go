@@ -0,0 +1,139 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	githubapi "github.com/google/go-github/v32/github"
+	"github.com/lensesio/tableprinter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/backport"
+	"github.com/openshift/patchmanager/pkg/cmd/util"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+// chainOptions holds values to drive the chain command.
+type chainOptions struct {
+	bugzillaAPIKey string
+	githubToken    string
+	jiraToken      string
+	bugID          string
+
+	recordDir string
+	replayDir string
+	snapshot  *snapshot.Store
+}
+
+// NewChainCommand creates a chain command.
+func NewChainCommand(ctx context.Context) *cobra.Command {
+	runOpts := chainOptions{}
+	cmd := &cobra.Command{
+		Use:   "chain <bug ID>",
+		Short: "Print the backport ladder of a bug (the bug clones and their pull requests across releases)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runOpts.bugID = args[0]
+			if err := runOpts.Complete(); err != nil {
+				klog.Exit(err)
+			}
+			if err := runOpts.Validate(); err != nil {
+				klog.Exit(err)
+			}
+			if err := runOpts.Run(ctx); err != nil {
+				klog.Exit(err)
+			}
+		},
+	}
+
+	runOpts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (r *chainOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&r.githubToken, "github-token", "", "Github Access Token (GITHUB_TOKEN env variable)")
+	fs.StringVar(&r.bugzillaAPIKey, "bugzilla-apikey", "", "Bugzilla API Key (BUGZILLA_APIKEY env variable)")
+	fs.StringVar(&r.jiraToken, "jira-token", "", "Jira Personal Access Token (JIRA_TOKEN env variable)")
+	fs.StringVar(&r.recordDir, "record", "", "Record everything fetched from Github and Bugzilla into given directory")
+	fs.StringVar(&r.replayDir, "replay", "", "Print the ladder offline from a directory recorded with --record")
+}
+
+func (r *chainOptions) Validate() error {
+	if len(r.githubToken) == 0 && !r.snapshot.Replaying() {
+		return fmt.Errorf("github-token flag must be specified or GITHUB_TOKEN environment must be set")
+	}
+	return nil
+}
+
+func (r *chainOptions) Complete() error {
+	if len(r.bugzillaAPIKey) == 0 {
+		r.bugzillaAPIKey = os.Getenv("BUGZILLA_APIKEY")
+	}
+	if len(r.jiraToken) == 0 {
+		r.jiraToken = os.Getenv("JIRA_TOKEN")
+	}
+	if len(r.githubToken) == 0 {
+		r.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	var err error
+	r.snapshot, err = util.NewSnapshotStore(r.recordDir, r.replayDir)
+	return err
+}
+
+type step struct {
+	Release      string `header:"Release"`
+	Bug          string `header:"Bug"`
+	Status       string `header:"Status"`
+	PullRequests string `header:"Pull Requests"`
+}
+
+func (r *chainOptions) Run(ctx context.Context) error {
+	lister := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, config.SearchConfig{}).WithSnapshot(r.snapshot)
+
+	// Jira issue keys (OCPBUGS-1234) are not numbers
+	trackerName := tracker.Jira
+	if _, err := strconv.Atoi(r.bugID); err == nil {
+		trackerName = tracker.Bugzilla
+	}
+	bug, err := lister.GetBug(trackerName, r.bugID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch bug %s: %v", r.bugID, err)
+	}
+
+	walker := &backport.Walker{
+		GetBug: func(id string) (*tracker.Issue, error) {
+			return lister.GetBug(trackerName, id)
+		},
+		GetPullRequest: func(prURL string) (*githubapi.PullRequest, error) {
+			return lister.GetPullRequestDetails(ctx, prURL)
+		},
+	}
+
+	out := []step{}
+	for _, s := range walker.Walk(bug).Steps {
+		row := step{Release: s.Release, Bug: s.BugID}
+		if s.Err != nil {
+			row.Status = fmt.Sprintf("error: %v", s.Err)
+			out = append(out, row)
+			continue
+		}
+		row.Status = strings.TrimSpace(s.Bug.Status + " " + s.Bug.Resolution)
+		pulls := []string{}
+		for _, p := range s.PullRequests {
+			pulls = append(pulls, fmt.Sprintf("%s (%s)", p.URL, p.State))
+		}
+		row.PullRequests = strings.Join(pulls, ", ")
+		out = append(out, row)
+	}
+	tableprinter.New(os.Stdout).Print(out)
+	return nil
+}
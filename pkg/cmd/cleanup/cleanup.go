@@ -25,6 +25,7 @@ type cleanupOptions struct {
 	release        string
 	githubToken    string
 	bugzillaAPIKey string
+	jiraToken      string
 	config         *config.PatchManagerConfig
 	configFile     string
 }
@@ -56,6 +57,7 @@ func NewCleanupCommand(ctx context.Context) *cobra.Command {
 func (r *cleanupOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&r.githubToken, "github-token", "", "Github Access Token (GITHUB_TOKEN env variable)")
 	fs.StringVar(&r.bugzillaAPIKey, "bugzilla-apikey", "", "Bugzilla API Key (BUGZILLA_APIKEY env variable)")
+	fs.StringVar(&r.jiraToken, "jira-token", "", "Jira Personal Access Token (JIRA_TOKEN env variable)")
 	fs.StringVar(&r.release, "release", "", "Release to use to list candidates")
 	fs.StringVar(&r.configFile, "config", os.Getenv("PATCHMANAGER_CONFIG"), "Path to a config file (PATCHMANAGER_CONFIG env variable)")
 }
@@ -68,6 +70,9 @@ func (r *cleanupOptions) Complete() error {
 	if len(r.bugzillaAPIKey) == 0 {
 		r.bugzillaAPIKey = os.Getenv("BUGZILLA_APIKEY")
 	}
+	if len(r.jiraToken) == 0 {
+		r.jiraToken = os.Getenv("JIRA_TOKEN")
+	}
 	var err error
 	r.config, err = config.GetConfig(r.configFile)
 	if err != nil {
@@ -88,7 +93,7 @@ type pull struct {
 }
 
 func (r *cleanupOptions) Run(ctx context.Context) error {
-	approved, err := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey).ListApprovedForRelease(ctx, r.release)
+	approved, _, err := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, r.config.SearchConfig).ListApprovedForRelease(ctx, r.release)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,265 @@
+package explain
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lensesio/tableprinter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/capacity"
+	"github.com/openshift/patchmanager/pkg/classifiers"
+	"github.com/openshift/patchmanager/pkg/cmd/util"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/rule"
+	"github.com/openshift/patchmanager/pkg/scoring"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+)
+
+// explainOptions holds values to drive the explain command.
+type explainOptions struct {
+	bugzillaAPIKey string
+	githubToken    string
+	jiraToken      string
+	release        string
+	pullRequestURL string
+
+	configFile string
+	config     *config.PatchManagerConfig
+	// flags tell whether --config was set explicitly
+	flags *pflag.FlagSet
+
+	recordDir string
+	replayDir string
+	snapshot  *snapshot.Store
+
+	classifier classifiers.BreakdownClassifier
+	rules      []rule.Ruler
+}
+
+// NewExplainCommand creates an explain command.
+func NewExplainCommand(ctx context.Context) *cobra.Command {
+	runOpts := explainOptions{}
+	cmd := &cobra.Command{
+		Use:   "explain <pull request URL>",
+		Short: "Explain how a single pull request is classified and ranked for given release",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runOpts.pullRequestURL = args[0]
+			if err := runOpts.Complete(); err != nil {
+				klog.Exit(err)
+			}
+			if err := runOpts.Validate(); err != nil {
+				klog.Exit(err)
+			}
+			if err := runOpts.Run(ctx); err != nil {
+				klog.Exit(err)
+			}
+		},
+	}
+
+	runOpts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (r *explainOptions) AddFlags(fs *pflag.FlagSet) {
+	r.flags = fs
+	fs.StringVar(&r.githubToken, "github-token", "", "Github Access Token (GITHUB_TOKEN env variable)")
+	fs.StringVar(&r.bugzillaAPIKey, "bugzilla-apikey", "", "Bugzilla API Key (BUGZILLA_APIKEY env variable)")
+	fs.StringVar(&r.jiraToken, "jira-token", "", "Jira Personal Access Token (JIRA_TOKEN env variable)")
+	fs.StringVar(&r.release, "release", "", "Target release (eg. 4.6, 4.7, etc...)")
+	fs.StringVar(&r.configFile, "config", os.Getenv("PATCHMANAGER_CONFIG"), "Path to a config file (PATCHMANAGER_CONFIG env variable)")
+	fs.StringVar(&r.recordDir, "record", "", "Record everything fetched from Github and Bugzilla into given directory")
+	fs.StringVar(&r.replayDir, "replay", "", "Explain the pull request offline from a directory recorded with --record")
+}
+
+func (r *explainOptions) Validate() error {
+	if len(r.bugzillaAPIKey) == 0 && !r.snapshot.Replaying() {
+		return fmt.Errorf("bugzilla-apikey flag must be specified or BUGZILLA_APIKEY environment must be set")
+	}
+	if len(r.githubToken) == 0 && !r.snapshot.Replaying() {
+		return fmt.Errorf("github-token flag must be specified or GITHUB_TOKEN environment must be set")
+	}
+	if len(r.release) == 0 {
+		return fmt.Errorf("you must specify target release (eg. --release=4.6)")
+	}
+	return nil
+}
+
+func (r *explainOptions) Complete() error {
+	if len(r.bugzillaAPIKey) == 0 {
+		r.bugzillaAPIKey = os.Getenv("BUGZILLA_APIKEY")
+	}
+	if len(r.jiraToken) == 0 {
+		r.jiraToken = os.Getenv("JIRA_TOKEN")
+	}
+	if len(r.githubToken) == 0 {
+		r.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	var err error
+	r.snapshot, err = util.NewSnapshotStore(r.recordDir, r.replayDir)
+	if err != nil {
+		return err
+	}
+	r.configFile = util.ConfigFile(r.snapshot, r.flags, r.configFile)
+	if len(r.configFile) == 0 {
+		return fmt.Errorf("you must provide valid config file (--config=config.yaml)")
+	}
+	r.config, err = config.GetConfig(r.configFile)
+	if err != nil {
+		return fmt.Errorf("unable to get config file %q: %v", r.configFile, err)
+	}
+	if len(r.config.Release) > 0 && len(r.release) == 0 {
+		r.release = r.config.Release
+	}
+	if err := util.RecordConfig(r.snapshot, r.config, r.release); err != nil {
+		return fmt.Errorf("unable to record config: %v", err)
+	}
+
+	r.classifier, err = classifiers.NewClassifierFromConfig(&r.config.ClassifiersConfigs, r.snapshot.Now())
+	if err != nil {
+		return fmt.Errorf("unable to configure classifiers: %v", err)
+	}
+	r.rules, err = rule.NewRulesFromConfig(&r.config.RulesConfig, r.release, r.snapshot.Now())
+	if err != nil {
+		return fmt.Errorf("unable to configure rules: %v", err)
+	}
+	return nil
+}
+
+type contribution struct {
+	Classifier string  `header:"Classifier"`
+	Score      float32 `header:"Score"`
+}
+
+type verdict struct {
+	Rule    string `header:"Rule"`
+	Verdict string `header:"Verdict"`
+	Reason  string `header:"Reason"`
+}
+
+func (r *explainOptions) Run(ctx context.Context) error {
+	lister := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, r.config.SearchConfig).WithSnapshot(r.snapshot)
+
+	// the candidate pool is needed to tell where the pull request would rank
+	candidates, _, err := lister.ListCandidatesForRelease(ctx, r.release)
+	if err != nil {
+		return err
+	}
+	var pullRequest *github.PullRequest
+	for i := range candidates {
+		if candidates[i].Issue.GetHTMLURL() == r.pullRequestURL {
+			pullRequest = candidates[i]
+			break
+		}
+	}
+	if pullRequest == nil {
+		klog.Warningf("WARNING: %s is not a z-stream candidate for release %s", r.pullRequestURL, r.release)
+		if pullRequest, err = lister.GetPullRequest(ctx, r.pullRequestURL); err != nil {
+			return err
+		}
+		candidates = append(candidates, pullRequest)
+	}
+	lister.PrefetchBugs(candidates)
+
+	fmt.Printf("Pull Request: %s\n", pullRequest.Issue.GetHTMLURL())
+	fmt.Printf("Title:        %s\n", pullRequest.Issue.GetTitle())
+	if err := pullRequest.BugError(); err != nil {
+		fmt.Printf("\nDecision: error (unable to fetch bug %s: %v)\n", pullRequest.BugID(), err)
+		return nil
+	}
+	bug := pullRequest.Bug()
+	component := strings.ToLower(strings.Join(bug.Component, "/"))
+	fmt.Printf("Bug:          %s (%s)\n", bug.URL, bug.Summary)
+	fmt.Printf("Component:    %s\n", component)
+	fmt.Printf("Severity:     %s\n", bug.Severity)
+	fmt.Printf("PM Score:     %s\n", bug.PMScore)
+
+	printer := tableprinter.New(os.Stdout)
+
+	// classifiers
+	score, breakdown := r.classifier.ScoreBreakdown(pullRequest)
+	contributions := []contribution{}
+	for _, c := range r.classifier.(*classifiers.MultiClassifier).Classifiers() {
+		contributions = append(contributions, contribution{Classifier: c.Name(), Score: breakdown[c.Name()]})
+	}
+	fmt.Printf("\nScore: %0.2f\n\n", score)
+	printer.Print(contributions)
+
+	// rules
+	verdicts := []verdict{}
+	refused := false
+	for _, rl := range r.rules {
+		messages, pass := rl.Evaluate(pullRequest)
+		v := verdict{Rule: rl.Name(), Verdict: "pass"}
+		if !pass {
+			v.Verdict = "refuse"
+			v.Reason = strings.Join(messages, ",")
+			refused = true
+		}
+		verdicts = append(verdicts, v)
+	}
+	fmt.Println()
+	printer.Print(verdicts)
+
+	// capacity
+	fmt.Println()
+	if group := config.ComponentGroupFor(&r.config.CapacityConfig, component); group != nil {
+		fmt.Printf("Capacity: component %s belongs to group %q with capacity %d shared by %d components\n", component, group.Name, group.Capacity, len(group.Components))
+		if limited, limit := config.ComponentLimit(group, component); limited {
+			fmt.Printf("Capacity: component %s is limited to %d picks within the group\n", component, limit)
+		}
+	} else {
+		fmt.Printf("Capacity: component %s is not in any group, default capacity is %d\n", component, r.config.CapacityConfig.MaximumDefaultPicksPerComponent)
+	}
+	owner, repo := github.GetPullMetaFromURL(pullRequest.Issue.GetHTMLURL())
+	fmt.Printf("Capacity: the pull request takes %g capacity points\n", capacity.Cost(&r.config.CapacityConfig.Cost, pullRequest, component, owner+"/"+repo))
+
+	if refused {
+		fmt.Printf("Rank: refused by the rules, the pull request will be skipped\n")
+		return nil
+	}
+	rank, total, err := r.rank(pullRequest, candidates)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rank: %d of %d candidates passing the rules (maximum total picks is %d)\n", rank, total, r.config.CapacityConfig.MaximumTotalPicks)
+	return nil
+}
+
+// rank score all candidates and return the position of the pull request among candidates passing the rules.
+func (r *explainOptions) rank(pullRequest *github.PullRequest, candidates []*github.PullRequest) (int, int, error) {
+	klog.Infof("Classifying %d z-stream candidate pull requests to rank %s ...", len(candidates), pullRequest.Issue.GetHTMLURL())
+	pool := scoring.NewWorkerPool(r.classifier)
+	if err := pool.Add(candidates...); err != nil {
+		return 0, 0, err
+	}
+	if err := pool.WaitForFinish(); err != nil {
+		return 0, 0, err
+	}
+
+	rules := rule.NewMultiRuler(r.rules...)
+	passing := []*github.PullRequest{}
+	for _, p := range candidates {
+		if p.Bug() == nil {
+			continue
+		}
+		if _, ok := rules.Evaluate(p); ok {
+			passing = append(passing, p)
+		}
+	}
+	scoring.SortByScore(passing)
+	for i := range passing {
+		if passing[i] == pullRequest {
+			return i + 1, len(passing), nil
+		}
+	}
+	return 0, len(passing), fmt.Errorf("%s not found in the candidate pool", pullRequest.Issue.GetHTMLURL())
+}
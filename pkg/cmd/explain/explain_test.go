@@ -0,0 +1,67 @@
+package explain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/classifiers"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/rule"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func testPull(number int, priority string, flags ...string) *github.PullRequest {
+	url := fmt.Sprintf("https://github.com/openshift/origin/pull/%d", number)
+	return fake.NewPullRequest(fake.PullRequest{
+		Issue: &githubapi.Issue{HTMLURL: &url},
+		Bug:   &tracker.Issue{ID: fmt.Sprint(number), Priority: priority, Flags: flags},
+	})
+}
+
+func TestRank(t *testing.T) {
+	r := &explainOptions{
+		classifier: classifiers.NewMultiClassifier(&classifiers.PriorityClassifier{
+			Config: &config.PriorityClassifierConfig{"urgent": 1, "high": 0.5},
+		}).(classifiers.BreakdownClassifier),
+		rules: []rule.Ruler{&rule.BugFlagsRule{Config: &config.BugFlagsRuleConfig{RefuseOnFlag: []string{"blocker-"}}}},
+	}
+	candidates := []*github.PullRequest{
+		testPull(1, "high"),
+		// refused by the rules and not ranked, even with the highest score
+		testPull(2, "urgent", "blocker-"),
+		testPull(3, "medium"),
+		// the bug can't be fetched, so the pull request is not ranked
+		fake.NewPullRequest(fake.PullRequest{BugErr: errors.New("not accessible")}),
+		testPull(5, "high"),
+		testPull(6, "urgent"),
+	}
+
+	tests := []struct {
+		pull     *github.PullRequest
+		expected int
+	}{
+		{pull: candidates[5], expected: 1},
+		// same score keeps the listing order
+		{pull: candidates[0], expected: 2},
+		{pull: candidates[4], expected: 3},
+		{pull: candidates[2], expected: 4},
+	}
+	for _, test := range tests {
+		rank, total, err := r.rank(test.pull, candidates)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.pull.Issue.GetHTMLURL(), err)
+		}
+		if rank != test.expected || total != 4 {
+			t.Errorf("%s: expected rank %d of 4, got %d of %d", test.pull.Issue.GetHTMLURL(), test.expected, rank, total)
+		}
+	}
+
+	if _, _, err := r.rank(candidates[1], candidates); err == nil {
+		t.Errorf("expected error for pull request refused by the rules")
+	}
+}
@@ -8,13 +8,16 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/openshift/patchmanager/pkg/cmd/util"
 	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
 
 	githubapi "github.com/google/go-github/v32/github"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/lensesio/tableprinter"
+	"github.com/openshift/patchmanager/pkg/api"
 	v1 "github.com/openshift/patchmanager/pkg/api/v1"
 	"github.com/openshift/patchmanager/pkg/github"
 	"github.com/spf13/cobra"
@@ -31,8 +34,15 @@ type listOptions struct {
 	release        string
 	githubToken    string
 	bugzillaAPIKey string
+	jiraToken      string
 	config         *config.PatchManagerConfig
 	configFile     string
+	// flags tell whether --config was set explicitly
+	flags *pflag.FlagSet
+
+	recordDir string
+	replayDir string
+	snapshot  *snapshot.Store
 }
 
 // NewListCommand creates a render command.
@@ -60,13 +70,17 @@ func NewListCommand(ctx context.Context) *cobra.Command {
 }
 
 func (r *listOptions) AddFlags(fs *pflag.FlagSet) {
+	r.flags = fs
 	fs.StringVar(&r.githubToken, "github-token", "", "Github Access Token (GITHUB_TOKEN env variable)")
 	fs.StringVar(&r.bugzillaAPIKey, "bugzilla-apikey", "", "Bugzilla API Key (BUGZILLA_APIKEY env variable)")
+	fs.StringVar(&r.jiraToken, "jira-token", "", "Jira Personal Access Token (JIRA_TOKEN env variable)")
 	fs.StringVarP(&r.inputFile, "file", "f", "", "Set input file to read the list of candidates")
 	fs.StringVar(&r.release, "release", "", "Release to use to list candidates")
 	fs.BoolVar(&r.candidates, "candidates", false, "List candidate PR's for a release")
 	fs.BoolVar(&r.approved, "approved", false, "List approved PR's for a release")
 	fs.StringVar(&r.configFile, "config", os.Getenv("PATCHMANAGER_CONFIG"), "Path to a config file (PATCHMANAGER_CONFIG env variable)")
+	fs.StringVar(&r.recordDir, "record", "", "Record everything fetched from Github and Bugzilla into given directory")
+	fs.StringVar(&r.replayDir, "replay", "", "List pull requests offline from a directory recorded with --record")
 }
 
 func (r *listOptions) Validate() error {
@@ -81,7 +95,15 @@ func (r *listOptions) Complete() error {
 	if len(r.bugzillaAPIKey) == 0 {
 		r.bugzillaAPIKey = os.Getenv("BUGZILLA_APIKEY")
 	}
+	if len(r.jiraToken) == 0 {
+		r.jiraToken = os.Getenv("JIRA_TOKEN")
+	}
 	var err error
+	r.snapshot, err = util.NewSnapshotStore(r.recordDir, r.replayDir)
+	if err != nil {
+		return err
+	}
+	r.configFile = util.ConfigFile(r.snapshot, r.flags, r.configFile)
 	r.config, err = config.GetConfig(r.configFile)
 	if err != nil {
 		return fmt.Errorf("unable to get config file %q: %v", r.configFile, err)
@@ -92,6 +114,9 @@ func (r *listOptions) Complete() error {
 	if len(r.config.Release) > 0 && len(r.release) == 0 {
 		r.release = r.config.Release
 	}
+	if err := util.RecordConfig(r.snapshot, r.config, r.release); err != nil {
+		return fmt.Errorf("unable to record config: %v", err)
+	}
 	if r.approved || r.candidates {
 		if len(r.release) == 0 {
 			return fmt.Errorf("you must specify target release to list pr's (eg. --release=4.6)")
@@ -101,10 +126,11 @@ func (r *listOptions) Complete() error {
 }
 
 type approvedPull struct {
-	URL      string  `header:"URL"`
-	Score    float32 `header:"Score"`
-	Decision string  `header:"Decision"`
-	Reason   string  `header:"Reason"`
+	URL       string  `header:"URL"`
+	Score     float32 `header:"Score"`
+	Breakdown string  `header:"Score Breakdown"`
+	Decision  string  `header:"Decision"`
+	Reason    string  `header:"Reason"`
 }
 
 type pull struct {
@@ -119,6 +145,8 @@ func colorizeDecision(d string) string {
 		return color.RedString("skip")
 	case "pick":
 		return color.GreenString("pick")
+	case "error":
+		return color.YellowString("error")
 	default:
 		return d
 	}
@@ -144,10 +172,11 @@ func (r *listOptions) Run(ctx context.Context) error {
 	out := []approvedPull{}
 	for _, c := range candidates.Items {
 		out = append(out, approvedPull{
-			URL:      c.PullRequest.URL,
-			Decision: colorizeDecision(c.PullRequest.Decision),
-			Reason:   c.PullRequest.DecisionReason,
-			Score:    c.PullRequest.Score,
+			URL:       c.PullRequest.URL,
+			Decision:  colorizeDecision(c.PullRequest.Decision),
+			Reason:    c.PullRequest.DecisionReason,
+			Score:     c.PullRequest.Score,
+			Breakdown: api.FormatScoreBreakdown(c.PullRequest.ScoreBreakdown),
 		})
 	}
 	printer.Print(out)
@@ -164,8 +193,8 @@ func stringifyLabels(labels []*githubapi.Label) string {
 }
 
 func (r *listOptions) RunListApproved(ctx context.Context) error {
-	lister := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey)
-	approved, err := lister.ListApprovedForRelease(ctx, r.release)
+	lister := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, r.config.SearchConfig).WithSnapshot(r.snapshot)
+	approved, _, err := lister.ListApprovedForRelease(ctx, r.release)
 	if err != nil {
 		return err
 	}
@@ -202,7 +231,7 @@ func statusToChecksString(statutes []*githubapi.RepoStatus) string {
 }
 
 func (r *listOptions) RunListCandidates(ctx context.Context) error {
-	candidates, err := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey).ListCandidatesForRelease(ctx, r.release)
+	candidates, _, err := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, r.config.SearchConfig).WithSnapshot(r.snapshot).ListCandidatesForRelease(ctx, r.release)
 	if err != nil {
 		return err
 	}
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/openshift/patchmanager/pkg/rule"
 
@@ -20,27 +24,68 @@ import (
 
 	"github.com/openshift/patchmanager/pkg/api"
 	v1 "github.com/openshift/patchmanager/pkg/api/v1"
+	"github.com/openshift/patchmanager/pkg/capacity"
 	"github.com/openshift/patchmanager/pkg/classifiers"
+	"github.com/openshift/patchmanager/pkg/cmd/util"
 	"github.com/openshift/patchmanager/pkg/config"
 	"github.com/openshift/patchmanager/pkg/github"
 	"github.com/openshift/patchmanager/pkg/scoring"
+	"github.com/openshift/patchmanager/pkg/snapshot"
 )
 
 // runOptions holds values to drive the start command.
 type runOptions struct {
 	bugzillaAPIKey string
+	jiraToken      string
 	githubToken    string
 	release        string
 	outFile        string
 
-	configFile string
-	config     *config.PatchManagerConfig
+	configFile        string
+	releaseConfigFile string
+	config            *config.PatchManagerConfig
+	// flags tell whether --config was set explicitly
+	flags *pflag.FlagSet
+
+	recordDir string
+	replayDir string
+	snapshot  *snapshot.Store
 
 	useCapacityPercent int
 	useCapacityCount   int
 
-	classifier classifiers.Classifier
-	rules      rule.Ruler
+	// releases are ordered by their priority
+	releases []*releaseOptions
+}
+
+// releaseOptions holds the config, classifiers and rules used to triage candidates of a single release.
+type releaseOptions struct {
+	release string
+	config  *config.PatchManagerConfig
+	// priority orders the releases picking from the shared capacity
+	priority int
+
+	// capacity is shared by all releases triaged in the run
+	capacity         *config.CapacityConfig
+	useCapacityCount int
+
+	classifier  classifiers.Classifier
+	rules       rule.Ruler
+	skipMessage *template.Template
+}
+
+// defaultSkipMessage is the decision reason for pull requests with score lower than the minimum score.
+const defaultSkipMessage = `automated classifiers have given this PR ` +
+	`{{if eq .MinimumScore 0.0}}a negative score{{else}}a score lower than {{printf "%0.2f" .MinimumScore}}{{end}} ` +
+	`meaning that it does not meet important merge criteria for this release; if you believe this PR is an exception, ` +
+	`please contact @patch-manager in coreos Slack`
+
+// skipMessageData is passed to the skip message template.
+type skipMessageData struct {
+	Score        float32
+	MinimumScore float32
+	URL          string
+	Component    string
 }
 
 // NewRunCommand creates a render command.
@@ -68,19 +113,24 @@ func NewRunCommand(ctx context.Context) *cobra.Command {
 }
 
 func (r *runOptions) AddFlags(fs *pflag.FlagSet) {
+	r.flags = fs
 	fs.StringVar(&r.githubToken, "github-token", "", "Github Access Token (GITHUB_TOKEN env variable)")
 	fs.StringVar(&r.bugzillaAPIKey, "bugzilla-apikey", "", "Bugzilla API Key (BUGZILLA_APIKEY env variable)")
-	fs.StringVar(&r.release, "release", "", "Target release (eg. 4.6, 4.7, etc...)")
+	fs.StringVar(&r.jiraToken, "jira-token", "", "Jira Personal Access Token (JIRA_TOKEN env variable)")
+	fs.StringVar(&r.release, "release", "", "Target release (eg. 4.6, 4.7, etc...) or comma separated releases sharing the capacity (eg. 4.6,4.7,4.8)")
 	fs.StringVar(&r.configFile, "config", os.Getenv("PATCHMANAGER_CONFIG"), "Path to a config file (PATCHMANAGER_CONFIG env variable)")
-	fs.StringVarP(&r.outFile, "output", "o", "", "Set output file instead of standard output")
+	fs.StringVar(&r.releaseConfigFile, "release-config", "", "Path to a config file of each release where {release} is replaced by the release (eg. config-{release}.yaml)")
+	fs.StringVarP(&r.outFile, "output", "o", "", "Set output file instead of standard output, with multiple releases {release} is replaced by the release (eg. candidates-{release}.yaml)")
 	fs.IntVar(&r.useCapacityPercent, "use-capacity-percent", 100, "How much capacity should be used to pick PR's (0-100)")
+	fs.StringVar(&r.recordDir, "record", "", "Record everything fetched from Github and Bugzilla into given directory")
+	fs.StringVar(&r.replayDir, "replay", "", "Replay the run offline from a directory recorded with --record")
 }
 
 func (r *runOptions) Validate() error {
-	if len(r.bugzillaAPIKey) == 0 {
+	if len(r.bugzillaAPIKey) == 0 && !r.snapshot.Replaying() {
 		return fmt.Errorf("bugzilla-apikey flag must be specified or BUGZILLA_APIKEY environment must be set")
 	}
-	if len(r.githubToken) == 0 {
+	if len(r.githubToken) == 0 && !r.snapshot.Replaying() {
 		return fmt.Errorf("github-token flag must be specified or GITHUB_TOKEN environment must be set")
 	}
 	if len(r.configFile) == 0 {
@@ -89,6 +139,19 @@ func (r *runOptions) Validate() error {
 	if r.useCapacityPercent > 100 || r.useCapacityPercent < 0 {
 		return fmt.Errorf("use-capacity-percent value must be between 0 and 100 (percent)")
 	}
+	if err := config.ValidateCapacity(&r.config.CapacityConfig); err != nil {
+		return err
+	}
+	if len(r.releases) == 0 {
+		return fmt.Errorf("release flag must be specified or release must be set in config")
+	}
+	seen := map[string]bool{}
+	for _, o := range r.releases {
+		if seen[o.release] {
+			return fmt.Errorf("release %s specified more than once", o.release)
+		}
+		seen[o.release] = true
+	}
 	return nil
 }
 
@@ -96,11 +159,20 @@ func (r *runOptions) Complete() error {
 	if len(r.bugzillaAPIKey) == 0 {
 		r.bugzillaAPIKey = os.Getenv("BUGZILLA_APIKEY")
 	}
+	if len(r.jiraToken) == 0 {
+		r.jiraToken = os.Getenv("JIRA_TOKEN")
+	}
 	if len(r.githubToken) == 0 {
 		r.githubToken = os.Getenv("GITHUB_TOKEN")
 	}
 
 	var err error
+	r.snapshot, err = util.NewSnapshotStore(r.recordDir, r.replayDir)
+	if err != nil {
+		return err
+	}
+	// replay with the config that was used for the recorded run, unless told otherwise
+	r.configFile = util.ConfigFile(r.snapshot, r.flags, r.configFile)
 	if len(r.configFile) == 0 {
 		return fmt.Errorf("you must provide valid config file (--config=config.yaml)")
 	}
@@ -111,42 +183,91 @@ func (r *runOptions) Complete() error {
 	if len(r.config.Release) > 0 && len(r.release) == 0 {
 		r.release = r.config.Release
 	}
-
-	r.classifier = classifiers.NewMultiClassifier(
-		&classifiers.SeverityClassifier{Config: &r.config.ClassifiersConfigs.Severities},
-		&classifiers.ComponentClassifier{Config: &r.config.ClassifiersConfigs.ComponentClassifier},
-		&classifiers.KeywordsClassifier{Config: &r.config.ClassifiersConfigs.KeywordsClassifier},
-		&classifiers.ProductManagementScoreClassifier{Config: &r.config.ClassifiersConfigs.PMScores},
-	)
-
-	r.rules = rule.NewMultiRuler(
-		&rule.PullRequestLabelRule{Config: &r.config.RulesConfig.PullRequestLabelConfig},
-	)
+	if err := util.RecordConfig(r.snapshot, r.config, r.release); err != nil {
+		return fmt.Errorf("unable to record config: %v", err)
+	}
+	// replay with the release configs that were used for the recorded run, unless told otherwise
+	if r.snapshot.Replaying() && len(r.releaseConfigFile) == 0 {
+		if recorded, _ := filepath.Glob(r.snapshot.Path(strings.ReplaceAll(snapshot.ReleaseConfigFile, "{release}", "*"))); len(recorded) > 0 {
+			r.releaseConfigFile = r.snapshot.Path(snapshot.ReleaseConfigFile)
+		}
+	}
 
 	// calculate how much PR's would be approved based on the "use capacity" percent
 	r.useCapacityCount = int((float32(r.config.CapacityConfig.MaximumTotalPicks) * 0.01) * float32(r.useCapacityPercent))
 	klog.Infof("Using %d%% of total QE capacity of %d PR's approved for ALL z-stream releases (max. %d picked)", r.useCapacityPercent, r.config.CapacityConfig.MaximumTotalPicks, r.useCapacityCount)
 
+	for _, release := range strings.Split(r.release, ",") {
+		release = strings.TrimSpace(release)
+		if len(release) == 0 {
+			continue
+		}
+		o, err := r.newReleaseOptions(release)
+		if err != nil {
+			return err
+		}
+		r.releases = append(r.releases, o)
+	}
+	r.sortReleases()
+
 	return nil
 }
 
-type capacityTracker struct {
-	config           *config.CapacityConfig
-	componentPicks   map[string]int
-	componentSkips   map[string]int
-	componentCounter map[string]int
+// sortReleases orders the releases by their priority, releases with higher priority pick first, releases with the same
+// priority keep the order they were specified in.
+func (r *runOptions) sortReleases() {
+	sort.SliceStable(r.releases, func(i, j int) bool {
+		return r.releases[i].priority > r.releases[j].priority
+	})
 }
 
-func (c capacityTracker) inc(component string) {
-	c.componentCounter[component] = c.componentCounter[component] + 1
-}
+// newReleaseOptions loads the release config (when --release-config is set) and configures the release classifiers and rules.
+// The capacity always comes from the --config file, so it is shared by all releases.
+func (r *runOptions) newReleaseOptions(release string) (*releaseOptions, error) {
+	o := &releaseOptions{
+		release:          release,
+		config:           r.config,
+		capacity:         &r.config.CapacityConfig,
+		useCapacityCount: r.useCapacityCount,
+	}
+	if len(r.releaseConfigFile) > 0 {
+		configFile := strings.ReplaceAll(r.releaseConfigFile, "{release}", release)
+		releaseConfig, err := config.GetConfig(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get config file %q for release %s: %v", configFile, release, err)
+		}
+		if err := util.RecordReleaseConfig(r.snapshot, releaseConfig, release); err != nil {
+			return nil, fmt.Errorf("unable to record config for release %s: %v", release, err)
+		}
+		if !reflect.DeepEqual(releaseConfig.CapacityConfig, config.CapacityConfig{}) {
+			klog.Warningf("WARNING: Capacity set in config file %q for release %s is ignored, the capacity of the --config file is shared by all releases", configFile, release)
+		}
+		o.config = releaseConfig
+	}
+	o.priority = o.config.Priority
+	if priority, ok := r.config.ReleasePriorities[release]; ok {
+		o.priority = priority
+	}
 
-func (c capacityTracker) hasCapacity(component string) bool {
-	isConfigured, maxComponentCapacity := config.ComponentCapacity(c.config, component)
-	if isConfigured {
-		return c.componentCounter[component] <= maxComponentCapacity
+	var err error
+	o.classifier, err = classifiers.NewClassifierFromConfig(&o.config.ClassifiersConfigs, r.snapshot.Now())
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure classifiers for release %s: %v", release, err)
+	}
+	configuredRules, err := rule.NewRulesFromConfig(&o.config.RulesConfig, release, r.snapshot.Now())
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure rules for release %s: %v", release, err)
 	}
-	return c.componentCounter[component] <= c.config.MaximumDefaultPicksPerComponent
+	o.rules = rule.NewMultiRuler(configuredRules...)
+
+	skipMessage := o.config.ThresholdsConfig.SkipMessage
+	if len(skipMessage) == 0 {
+		skipMessage = defaultSkipMessage
+	}
+	if o.skipMessage, err = template.New("skipMessage").Parse(skipMessage); err != nil {
+		return nil, fmt.Errorf("invalid skip message template for release %s: %v", release, err)
+	}
+	return o, nil
 }
 
 func componentName(p []string) string {
@@ -154,35 +275,192 @@ func componentName(p []string) string {
 }
 
 func (r *runOptions) Run(ctx context.Context) error {
-	pullsToReview, err := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey).ListCandidatesForRelease(ctx, r.release)
-	if err != nil {
+	if groups := len(r.config.CapacityConfig.Groups); groups > 0 {
+		klog.Infof("Capacity configuration for %d groups loaded (default per component: %d)", groups, r.config.CapacityConfig.MaximumDefaultPicksPerComponent)
+	}
+
+	// all releases pick from the same capacity, in the order of their priority
+	capacityTracker := capacity.NewTracker(&r.config.CapacityConfig)
+	outputs := make([][]byte, len(r.releases))
+	summary := v1.ReleaseSummaryList{}
+	for i, o := range r.releases {
+		used := capacityTracker.Used()
+		candidates, searchStats, err := o.triage(ctx, r, capacityTracker)
+		if err != nil {
+			return fmt.Errorf("release %s: %v", o.release, err)
+		}
+		if outputs[i], err = yaml.Marshal(api.NewCandidateList(candidates)); err != nil {
+			return err
+		}
+		releaseSummary := v1.ReleaseSummary{
+			Release:        o.release,
+			Priority:       o.priority,
+			CandidatesFile: r.outputFile(o.release),
+			SearchTotal:    searchStats.Total,
+			Truncated:      searchStats.Truncated,
+			Total:          len(candidates),
+			Cost:           capacityTracker.Used() - used,
+		}
+		for _, c := range candidates {
+			switch c.Decision {
+			case "pick":
+				releaseSummary.Picks++
+			case "skip":
+				releaseSummary.Skips++
+			default:
+				releaseSummary.Errors++
+			}
+		}
+		summary.Items = append(summary.Items, releaseSummary)
+	}
+
+	printer := tableprinter.New(os.Stdout)
+	fmt.Println()
+	printer.Print(capacityTracker.Metrics())
+	fmt.Println()
+	rows := make([]releaseSummaryRow, len(summary.Items))
+	for i, s := range summary.Items {
+		rows[i] = releaseSummaryRow{Release: s.Release, Priority: s.Priority, SearchTotal: strconv.Itoa(s.SearchTotal), Total: s.Total,
+			Picks: s.Picks, Skips: s.Skips, Errors: s.Errors, Capacity: fmt.Sprintf("%g/%d", s.Cost, r.useCapacityCount)}
+		if s.Truncated {
+			rows[i].SearchTotal += " (truncated)"
+		}
+	}
+	printer.Print(rows)
+	fmt.Println()
+
+	for i, o := range r.releases {
+		if err := r.writeOutput(r.outputFile(o.release), o.release, outputs[i]); err != nil {
+			return err
+		}
+	}
+	if len(r.releases) > 1 && len(r.outFile) > 0 {
+		out, err := yaml.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		return r.writeOutput(r.summaryFile(), "summary", out)
+	}
+	return nil
+}
+
+// releaseSummaryRow is the summary of a release printed as a table.
+type releaseSummaryRow struct {
+	Release  string `header:"Release"`
+	Priority int    `header:"Priority"`
+	// SearchTotal is the number of search results reported by Github, Total is the number of candidates
+	SearchTotal string `header:"Search Results"`
+	Total       int    `header:"Total"`
+	Picks       int    `header:"Picks"`
+	Skips       int    `header:"Skips"`
+	Errors      int    `header:"Errors"`
+	Capacity    string `header:"Capacity"`
+}
+
+// outputFile returns the file the release candidates are saved to. "{release}" in the output flag is replaced by the
+// release, with multiple releases and no "{release}" the release is added before the file extension (eg. "candidates-4.7.yaml").
+func (r *runOptions) outputFile(release string) string {
+	if strings.Contains(r.outFile, "{release}") {
+		return strings.ReplaceAll(r.outFile, "{release}", release)
+	}
+	if len(r.outFile) == 0 || len(r.releases) <= 1 {
+		return r.outFile
+	}
+	ext := filepath.Ext(r.outFile)
+	return strings.TrimSuffix(r.outFile, ext) + "-" + release + ext
+}
+
+// summaryFile returns the file the summary of a multi-release run is saved to. When "{release}" is part of the directory
+// (eg. "{release}/candidates.yaml"), the summary is saved next to the release directories ("summary.yaml").
+func (r *runOptions) summaryFile() string {
+	dir, base := filepath.Split(r.outFile)
+	switch {
+	case !strings.Contains(r.outFile, "{release}"):
+		ext := filepath.Ext(r.outFile)
+		return strings.TrimSuffix(r.outFile, ext) + "-summary" + ext
+	case !strings.Contains(dir, "{release}"):
+		return filepath.Join(dir, strings.ReplaceAll(base, "{release}", "summary"))
+	default:
+		prefix := r.outFile[:strings.Index(r.outFile, "{release}")]
+		return filepath.Join(filepath.Dir(prefix+"_"), "summary"+filepath.Ext(r.outFile))
+	}
+}
+
+func (r *runOptions) writeOutput(outFile, name string, out []byte) error {
+	// to file
+	if len(outFile) > 0 {
+		if err := os.MkdirAll(filepath.Dir(outFile), os.ModePerm); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(outFile, out, os.ModePerm); err != nil {
+			return err
+		}
+		klog.Infof("Result saved to %q", outFile)
+		return nil
+	}
+
+	// standard output
+	if len(r.releases) > 1 {
+		if _, err := fmt.Fprintf(os.Stdout, "---\n# release %s\n", name); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(os.Stdout, "%s\n", string(out)); err != nil {
 		return err
 	}
+	return nil
+}
 
-	if capacity := len(r.config.CapacityConfig.Groups); capacity > 0 {
-		klog.Infof("Capacity configuration for %d groups loaded (default per component: %d)", capacity, r.config.CapacityConfig.MaximumDefaultPicksPerComponent)
+// triage lists, classifies and selects the release candidates, picks are recorded in the capacity tracker shared by all releases.
+func (o *releaseOptions) triage(ctx context.Context, r *runOptions, capacityTracker *capacity.Tracker) ([]v1.Candidate, github.SearchStats, error) {
+	lister := github.NewPullRequestLister(ctx, r.githubToken, r.bugzillaAPIKey, r.jiraToken, o.config.SearchConfig).WithSnapshot(r.snapshot)
+	pullsToReview, searchStats, err := lister.ListCandidatesForRelease(ctx, o.release)
+	if err != nil {
+		return nil, searchStats, err
 	}
+	klog.Infof("Found %d z-stream candidate pull requests for release %s (%d search results)", len(pullsToReview), o.release, searchStats.Total)
+
+	// fetch all bugs in batches before classifiers ask for them one by one
+	lister.PrefetchBugs(pullsToReview)
 
 	// assign score to each pull request by running it trough set of classifiers
 	progress := pb.StartNew(len(pullsToReview))
-	pool := scoring.NewWorkerPool(r.classifier).WithCallback(func(interface{}) {
+	pool := scoring.NewWorkerPool(o.classifier).WithCallback(func(interface{}) {
 		progress.Increment()
 	})
 	if err := pool.Add(pullsToReview...); err != nil {
-		return err
+		return nil, searchStats, err
 	}
 
 	klog.Infof("Wait to finish classifying %d z-stream candidate pull requests ...", len(pullsToReview))
 	if err := pool.WaitForFinish(); err != nil {
-		return err
+		return nil, searchStats, err
 	}
 	progress.Finish()
 
+	candidates, err := o.decide(pullsToReview, capacityTracker)
+	return candidates, searchStats, err
+}
+
+// decide applies the rules to the classified pull requests and selects the picks within the capacity, picks are recorded
+// in the capacity tracker shared by all releases.
+func (o *releaseOptions) decide(pullsToReview []*github.PullRequest, capacityTracker *capacity.Tracker) ([]v1.Candidate, error) {
 	candidates := []v1.Candidate{}
 
 	pullsToClassify := []*github.PullRequest{}
 	for i, p := range pullsToReview {
-		decisions, ok := r.rules.Evaluate(pullsToReview[i])
+		// bugs we failed to fetch can't be classified, let the patch manager decide
+		if err := p.BugError(); err != nil {
+			candidates = append(candidates, v1.Candidate{
+				PullRequestURL: p.Issue.GetHTMLURL(),
+				Description:    p.Issue.GetTitle(),
+				BugNumber:      p.BugID(),
+				Decision:       "error",
+				DecisionReason: fmt.Sprintf("unable to fetch bug %s: %v", p.BugID(), err),
+			})
+			continue
+		}
+		decisions, ok := o.rules.Evaluate(pullsToReview[i])
 		if ok {
 			pullsToClassify = append(pullsToClassify, pullsToReview[i])
 			continue
@@ -192,117 +470,67 @@ func (r *runOptions) Run(ctx context.Context) error {
 			Score:          0,
 			Description:    p.Bug().Summary,
 			PullRequestURL: p.Issue.GetHTMLURL(),
-			BugNumber:      fmt.Sprintf("%d", p.Bug().ID),
+			BugNumber:      p.Bug().ID,
+			BugURL:         p.Bug().URL,
 			Component:      componentName(p.Bug().Component),
 			Severity:       p.Bug().Severity,
 			Decision:       "skip",
 			DecisionReason: strings.Join(decisions, ","),
 		})
 	}
-	klog.Infof("%d pull requests refused by the rules", len(candidates))
+	klog.Infof("%d pull requests refused by the rules or failed to fetch the bug", len(candidates))
 
 	// order the pending pull requests by score
-	sort.Slice(pullsToClassify, func(i, j int) bool {
-		return pullsToClassify[i].Score > pullsToClassify[j].Score
-	})
-
-	capacity := &capacityTracker{
-		config:           &r.config.CapacityConfig,
-		componentCounter: map[string]int{},
-		componentPicks:   map[string]int{},
-		componentSkips:   map[string]int{},
-	}
-
-	// decide which pull requests we are going to pick based on the componentCounter
-	totalPicks := 0
-
-	for _, p := range pullsToClassify {
-		decision := "pick"
-		decisionReason := fmt.Sprintf("picked for z-stream with score %0.2f", p.Score)
-
-		// increment capacity counter for this component
-		capacity.inc(componentName(p.Bug().Component))
-
-		if p.Score < 0.0 {
-			// if the component has a negative score, it is unlikely this PR is meeting a important criteria
-			decision = "skip"
-			decisionReason = fmt.Sprintf("automated classifiers have given this PR a negative score meaning that " +
-				"it does not meet important merge criteria for this release; if you believe this PR is an exception, " +
-				"please contact @patch-manager in coreos Slack")
-		} else if !capacity.hasCapacity(componentName(p.Bug().Component)) {
-			// if component has no capacity to take this pick
-			decision = "skip"
-			_, componentCapacity := config.ComponentCapacity(&r.config.CapacityConfig, componentName(p.Bug().Component))
-			decisionReason = fmt.Sprintf("maximum allowed picks for component %s is %d", componentName(p.Bug().Component), componentCapacity)
-		}
+	scoring.SortByScore(pullsToClassify)
 
-		// if there are more picks than total picks allowed
-		if decision == "pick" {
-			totalPicks++
+	// decide which pull requests we are going to pick based on the capacity
+	costs := make([]float32, len(pullsToClassify))
+	for i, p := range pullsToClassify {
+		costs[i] = capacity.Cost(&o.capacity.Cost, p, componentName(p.Bug().Component), repoName(p))
+	}
+	var (
+		selected []selection
+		err      error
+	)
+	if o.capacity.Selection == config.OptimalSelection {
+		// the greedy selection runs on a copy of the capacity only to report the difference
+		greedy, err := o.greedySelection(pullsToClassify, costs, capacityTracker.Copy())
+		if err != nil {
+			return nil, err
 		}
-		if totalPicks > r.useCapacityCount {
-			decision = "skip"
-			decisionReason = fmt.Sprintf("maximum QE capacity for all z-stream is %d", r.config.CapacityConfig.MaximumTotalPicks)
+		if selected, err = o.optimalSelection(pullsToClassify, costs, greedy, capacityTracker); err != nil {
+			return nil, err
 		}
-
-		if decision == "pick" {
-			capacity.componentPicks[componentName(p.Bug().Component)]++
-		} else {
-			capacity.componentSkips[componentName(p.Bug().Component)]++
+		printSelectionDiff(pullsToClassify, greedy, selected)
+	} else if len(o.capacity.Allocation) > 0 && o.capacity.Allocation != config.ScoreAllocation {
+		if selected, err = o.fairShareSelection(pullsToClassify, costs, capacityTracker); err != nil {
+			return nil, err
 		}
+	} else if selected, err = o.greedySelection(pullsToClassify, costs, capacityTracker); err != nil {
+		return nil, err
+	}
 
+	for i, p := range pullsToClassify {
 		// add to candidate list
-		candidates = append(candidates, v1.Candidate{
+		candidate := v1.Candidate{
 			PMScore:        p.Bug().PMScore,
 			Score:          p.Score,
+			ScoreBreakdown: p.ScoreBreakdown,
 			Description:    p.Bug().Summary,
 			PullRequestURL: p.Issue.GetHTMLURL(),
-			BugNumber:      fmt.Sprintf("%d", p.Bug().ID),
+			BugNumber:      p.Bug().ID,
+			BugURL:         p.Bug().URL,
 			Component:      componentName(p.Bug().Component),
 			Severity:       p.Bug().Severity,
-			Decision:       decision,
-			DecisionReason: decisionReason,
-		})
-	}
-
-	out, err := yaml.Marshal(api.NewCandidateList(candidates))
-	if err != nil {
-		return err
-	}
-
-	metrics := []componentMetric{}
-	for name, count := range capacity.componentCounter {
-		metrics = append(metrics, componentMetric{
-			Component: name,
-			Total:     count,
-			Picks:     capacity.componentPicks[name],
-			Skips:     capacity.componentSkips[name],
-		})
-	}
-	printer := tableprinter.New(os.Stdout)
-	fmt.Println()
-	printer.Print(metrics)
-	fmt.Println()
-
-	// to file
-	if len(r.outFile) > 0 {
-		if err := ioutil.WriteFile(r.outFile, out, os.ModePerm); err != nil {
-			return err
+			Decision:       selected[i].decision,
+			DecisionReason: selected[i].reason,
+			Threshold:      selected[i].threshold,
 		}
-		klog.Infof("Result saved to %q", r.outFile)
-		return nil
-	}
-
-	// standard output
-	if _, err = fmt.Fprintf(os.Stdout, "%s\n", string(out)); err != nil {
-		return err
+		// the cost is recorded only when configured, otherwise every pull request takes 1 point
+		if config.HasCost(o.capacity.Cost) {
+			candidate.Cost = costs[i]
+		}
+		candidates = append(candidates, candidate)
 	}
-	return nil
-}
-
-type componentMetric struct {
-	Component string `header:"Component Name"`
-	Total     int    `header:"Total"`
-	Picks     int    `header:"Picks"`
-	Skips     int    `header:"Skips"`
+	return candidates, nil
 }
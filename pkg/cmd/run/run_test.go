@@ -0,0 +1,94 @@
+package run
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/capacity"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+func TestOutputFiles(t *testing.T) {
+	tests := []struct {
+		outFile  string
+		releases int
+		release  string
+		summary  string
+	}{
+		{outFile: "candidates.yaml", releases: 1, release: "candidates.yaml"},
+		{outFile: "candidates-{release}.yaml", releases: 1, release: "candidates-4.7.yaml"},
+		{outFile: "candidates.yaml", releases: 2, release: "candidates-4.7.yaml", summary: "candidates-summary.yaml"},
+		{outFile: "out/candidates-{release}.yaml", releases: 2, release: "out/candidates-4.7.yaml", summary: "out/candidates-summary.yaml"},
+		{outFile: "{release}/candidates.yaml", releases: 2, release: "4.7/candidates.yaml", summary: "summary.yaml"},
+		{outFile: "out/release-{release}/candidates.yaml", releases: 2, release: "out/release-4.7/candidates.yaml", summary: "out/summary.yaml"},
+	}
+	for _, test := range tests {
+		r := &runOptions{outFile: test.outFile, releases: make([]*releaseOptions, test.releases)}
+		if file := r.outputFile("4.7"); file != test.release {
+			t.Errorf("%s: expected release file %q, got %q", test.outFile, test.release, file)
+		}
+		if test.releases > 1 {
+			if file := r.summaryFile(); file != test.summary {
+				t.Errorf("%s: expected summary file %q, got %q", test.outFile, test.summary, file)
+			}
+		}
+	}
+}
+
+func TestReleasesShareCapacity(t *testing.T) {
+	r := &runOptions{
+		config: &config.PatchManagerConfig{
+			CapacityConfig:    config.CapacityConfig{MaximumTotalPicks: 3, MaximumDefaultPicksPerComponent: 10},
+			ReleasePriorities: map[string]int{"4.8": 10},
+		},
+		useCapacityCount: 3,
+	}
+	for _, release := range []string{"4.6", "4.7", "4.8"} {
+		o, err := r.newReleaseOptions(release)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.releases = append(r.releases, o)
+	}
+	r.sortReleases()
+
+	// pull requests of each release ordered by score
+	pulls := map[string][]*github.PullRequest{
+		"4.6": {testPull(1, "etcd", 0.9)},
+		"4.7": {testPull(2, "etcd", 0.8), testPull(3, "etcd", 0.7)},
+		"4.8": {testPull(4, "etcd", 0.2), testPull(5, "etcd", 0.1)},
+	}
+	expected := []struct {
+		release   string
+		decisions []string
+	}{
+		// release with higher priority picks first, releases with the same priority keep their order
+		{release: "4.8", decisions: []string{"pick", "pick"}},
+		{release: "4.6", decisions: []string{"pick"}},
+		{release: "4.7", decisions: []string{"skip", "skip"}},
+	}
+	capacityTracker := capacity.NewTracker(&r.config.CapacityConfig)
+	for i, o := range r.releases {
+		if o.release != expected[i].release {
+			t.Fatalf("expected release %s at position %d, got %s", expected[i].release, i, o.release)
+		}
+		candidates, err := o.decide(pulls[o.release], capacityTracker)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decisions := []string{}
+		for _, c := range candidates {
+			decisions = append(decisions, c.Decision)
+			if c.Decision == "skip" && c.DecisionReason != "maximum QE capacity for all z-stream is 3" {
+				t.Errorf("expected %s to be skipped because of the shared capacity, got %q", c.PullRequestURL, c.DecisionReason)
+			}
+		}
+		if !reflect.DeepEqual(decisions, expected[i].decisions) {
+			t.Errorf("release %s: expected %v, got %v", o.release, expected[i].decisions, decisions)
+		}
+	}
+	if used := capacityTracker.Used(); used != 3 {
+		t.Errorf("expected all 3 capacity points used, got %g", used)
+	}
+}
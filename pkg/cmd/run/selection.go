@@ -0,0 +1,258 @@
+package run
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/lensesio/tableprinter"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/openshift/patchmanager/pkg/api/v1"
+	"github.com/openshift/patchmanager/pkg/capacity"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// selection is the decision about a single pull request.
+type selection struct {
+	decision  string
+	reason    string
+	threshold string
+}
+
+// optimalSelectionBonus is added to the score of every pull request passing the minimum score, so the optimal selection
+// prefers more picks when the total score is the same.
+const optimalSelectionBonus = 0.001
+
+func repoName(p *github.PullRequest) string {
+	owner, repo := github.GetPullMetaFromURL(p.Issue.GetHTMLURL())
+	return owner + "/" + repo
+}
+
+// isMustPick returns true when the pull request is picked regardless of its component capacity.
+func (r *releaseOptions) isMustPick(p *github.PullRequest) bool {
+	mustPickScore := r.config.ThresholdsConfig.MustPickScore
+	return mustPickScore != nil && p.Score >= *mustPickScore
+}
+
+// lowScoreMessage returns the decision reason for pull request with score lower than the minimum score.
+func (r *releaseOptions) lowScoreMessage(p *github.PullRequest) (string, error) {
+	message := &strings.Builder{}
+	if err := r.skipMessage.Execute(message, skipMessageData{
+		Score:        p.Score,
+		MinimumScore: r.config.ThresholdsConfig.MinimumScore,
+		URL:          p.Issue.GetHTMLURL(),
+		Component:    componentName(p.Bug().Component),
+	}); err != nil {
+		return "", fmt.Errorf("unable to render skip message: %v", err)
+	}
+	return message.String(), nil
+}
+
+// greedySelection picks pull requests (ordered by score) until the component, group or total capacity is exhausted.
+// Picks and skips are recorded in the capacity tracker.
+func (r *releaseOptions) greedySelection(pulls []*github.PullRequest, costs []float32, capacityTracker *capacity.Tracker) ([]selection, error) {
+	result := make([]selection, len(pulls))
+	for i, p := range pulls {
+		var err error
+		if result[i], err = r.greedyDecision(p, costs[i], capacityTracker); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// greedyDecision picks the pull request when it has the minimum score and fits into the capacity and records the
+// decision in the capacity tracker.
+func (r *releaseOptions) greedyDecision(p *github.PullRequest, cost float32, capacityTracker *capacity.Tracker) (selection, error) {
+	thresholds := r.config.ThresholdsConfig
+	decision := "pick"
+	decisionReason := fmt.Sprintf("picked for z-stream with score %0.2f", p.Score)
+	threshold := ""
+
+	if p.Score < thresholds.MinimumScore {
+		// if the pull request score is too low, it is unlikely this PR is meeting a important criteria
+		decision = "skip"
+		threshold = v1.ThresholdMinimumScore
+		message, err := r.lowScoreMessage(p)
+		if err != nil {
+			return selection{}, err
+		}
+		decisionReason = message
+	} else if ok, reason := capacityTracker.HasCapacity(componentName(p.Bug().Component), repoName(p), cost); !ok {
+		if r.isMustPick(p) {
+			// urgent pull requests are picked over the component capacity
+			threshold = v1.ThresholdMustPick
+			decisionReason = fmt.Sprintf("picked for z-stream with score %0.2f over must-pick score %0.2f although %s",
+				p.Score, *thresholds.MustPickScore, reason)
+		} else {
+			// if component has no capacity to take this pick
+			decision = "skip"
+			decisionReason = reason
+		}
+	}
+
+	// if the pick does not fit into total capacity
+	if decision == "pick" && capacity.Exceeds(capacityTracker.Used(), cost, r.useCapacityCount) {
+		decision = "skip"
+		decisionReason = fmt.Sprintf("maximum QE capacity for all z-stream is %d", r.capacity.MaximumTotalPicks)
+		threshold = ""
+	}
+
+	if decision == "pick" {
+		capacityTracker.Pick(componentName(p.Bug().Component), repoName(p), cost)
+	} else {
+		capacityTracker.Skip(componentName(p.Bug().Component))
+	}
+	return selection{decision: decision, reason: decisionReason, threshold: threshold}, nil
+}
+
+// fairShareSelection lets every component group pick its best pull requests within its share of the total capacity
+// not yet used in the capacity tracker first, the rest of pull requests is then decided by the greedy selection to fill
+// the unused shares in the score order. Picks and skips are recorded in the capacity tracker.
+func (r *releaseOptions) fairShareSelection(pulls []*github.PullRequest, costs []float32, capacityTracker *capacity.Tracker) ([]selection, error) {
+	budget := r.useCapacityCount - int(math.Ceil(float64(capacityTracker.Used())-1e-4))
+	shares := capacity.FairShares(r.capacity, budget)
+	for _, group := range r.capacity.Groups {
+		klog.V(2).Infof("Fair share of group %s is %d of %d", group.Name, shares[group.Name], budget)
+	}
+
+	result := make([]selection, len(pulls))
+	sharePoints := map[string]float32{}
+	for i, p := range pulls {
+		group := config.ComponentGroupFor(r.capacity, componentName(p.Bug().Component))
+		if group == nil || p.Score < r.config.ThresholdsConfig.MinimumScore || capacity.Exceeds(sharePoints[group.Name], costs[i], shares[group.Name]) {
+			continue
+		}
+		if ok, _ := capacityTracker.HasCapacity(componentName(p.Bug().Component), repoName(p), costs[i]); !ok || capacity.Exceeds(capacityTracker.Used(), costs[i], r.useCapacityCount) {
+			continue
+		}
+		sharePoints[group.Name] += costs[i]
+		capacityTracker.Pick(componentName(p.Bug().Component), repoName(p), costs[i])
+		result[i] = selection{
+			decision: "pick",
+			reason:   fmt.Sprintf("picked for z-stream with score %0.2f within the fair share %d of group %s", p.Score, shares[group.Name], group.Name),
+		}
+	}
+
+	// backfill the unused shares
+	for i, p := range pulls {
+		if result[i].decision == "pick" {
+			continue
+		}
+		var err error
+		if result[i], err = r.greedyDecision(p, costs[i], capacityTracker); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// optimalSelection picks pull requests with the highest total score that fit into the capacity not yet used in the
+// capacity tracker. Picks and skips are recorded in the capacity tracker.
+func (r *releaseOptions) optimalSelection(pulls []*github.PullRequest, costs []float32, greedy []selection, capacityTracker *capacity.Tracker) ([]selection, error) {
+	thresholds := r.config.ThresholdsConfig
+	items := []capacity.Item{}
+	itemPulls := []int{}
+	for i, p := range pulls {
+		if p.Score < thresholds.MinimumScore {
+			continue
+		}
+		items = append(items, capacity.Item{
+			Component: componentName(p.Bug().Component),
+			Repo:      repoName(p),
+			Cost:      costs[i],
+			// the minimum score only filters the candidates, the solver maximizes the total score of the picks
+			Value:    float64(p.Score) + optimalSelectionBonus,
+			MustPick: r.isMustPick(p),
+		})
+		itemPulls = append(itemPulls, i)
+	}
+	picked, optimal := capacity.Solve(r.capacity, items, r.useCapacityCount, capacityTracker)
+	if !optimal {
+		klog.Warningf("WARNING: Optimal selection search was stopped, the selection might not be optimal")
+	}
+
+	result := make([]selection, len(pulls))
+	isPicked := make([]bool, len(pulls))
+	for j, i := range itemPulls {
+		isPicked[i] = picked[j]
+	}
+
+	// record all picks first, so the reasons of skips reflect the final capacity
+	for i, p := range pulls {
+		if !isPicked[i] {
+			continue
+		}
+		result[i] = selection{decision: "pick", reason: fmt.Sprintf("picked for z-stream with score %0.2f by optimal selection", p.Score)}
+		if ok, reason := capacityTracker.HasCapacity(componentName(p.Bug().Component), repoName(p), costs[i]); !ok && r.isMustPick(p) {
+			result[i].threshold = v1.ThresholdMustPick
+			result[i].reason = fmt.Sprintf("picked for z-stream with score %0.2f over must-pick score %0.2f although %s",
+				p.Score, *thresholds.MustPickScore, reason)
+		}
+		capacityTracker.Pick(componentName(p.Bug().Component), repoName(p), costs[i])
+	}
+	for i, p := range pulls {
+		if isPicked[i] {
+			continue
+		}
+		result[i] = selection{decision: "skip"}
+		switch ok, reason := capacityTracker.HasCapacity(componentName(p.Bug().Component), repoName(p), costs[i]); {
+		case p.Score < thresholds.MinimumScore:
+			result[i].threshold = v1.ThresholdMinimumScore
+			message, err := r.lowScoreMessage(p)
+			if err != nil {
+				return nil, err
+			}
+			result[i].reason = message
+		case capacity.Exceeds(capacityTracker.Used(), costs[i], r.useCapacityCount):
+			result[i].reason = fmt.Sprintf("maximum QE capacity for all z-stream is %d", r.capacity.MaximumTotalPicks)
+		case !ok:
+			result[i].reason = reason
+		default:
+			result[i].reason = "skipped by optimal selection in favor of pull requests with higher total score"
+		}
+		if greedy[i].decision == "pick" {
+			result[i].reason += " (greedy selection would pick it)"
+		}
+		capacityTracker.Skip(componentName(p.Bug().Component))
+	}
+	return result, nil
+}
+
+type selectionDiff struct {
+	URL     string  `header:"URL"`
+	Score   float32 `header:"Score"`
+	Greedy  string  `header:"Greedy"`
+	Optimal string  `header:"Optimal"`
+}
+
+// printSelectionDiff prints pull requests where the optimal selection differs from the greedy one.
+func printSelectionDiff(pulls []*github.PullRequest, greedy, optimal []selection) {
+	greedyScore, optimalScore := float32(0), float32(0)
+	greedyPicks, optimalPicks := 0, 0
+	diff := []selectionDiff{}
+	for i, p := range pulls {
+		if greedy[i].decision == "pick" {
+			greedyScore += p.Score
+			greedyPicks++
+		}
+		if optimal[i].decision == "pick" {
+			optimalScore += p.Score
+			optimalPicks++
+		}
+		if greedy[i].decision != optimal[i].decision {
+			diff = append(diff, selectionDiff{URL: p.Issue.GetHTMLURL(), Score: p.Score, Greedy: greedy[i].decision, Optimal: optimal[i].decision})
+		}
+	}
+	klog.Infof("Optimal selection picked %d pull requests with total score %0.2f, greedy selection would pick %d with total score %0.2f",
+		optimalPicks, optimalScore, greedyPicks, greedyScore)
+	if len(diff) == 0 {
+		klog.Infof("Optimal selection is the same as greedy selection")
+		return
+	}
+	fmt.Println()
+	tableprinter.New(os.Stdout).Print(diff)
+}
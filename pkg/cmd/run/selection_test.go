@@ -0,0 +1,117 @@
+package run
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/capacity"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestDefaultSkipMessage(t *testing.T) {
+	tests := []struct {
+		minimumScore float32
+		expected     string
+	}{
+		{
+			minimumScore: 0,
+			expected: "automated classifiers have given this PR a negative score meaning that it does not meet important merge criteria " +
+				"for this release; if you believe this PR is an exception, please contact @patch-manager in coreos Slack",
+		},
+		{
+			minimumScore: 0.5,
+			expected: "automated classifiers have given this PR a score lower than 0.50 meaning that it does not meet important merge criteria " +
+				"for this release; if you believe this PR is an exception, please contact @patch-manager in coreos Slack",
+		},
+	}
+	skipMessage := template.Must(template.New("skipMessage").Parse(defaultSkipMessage))
+	for _, test := range tests {
+		message := &strings.Builder{}
+		if err := skipMessage.Execute(message, skipMessageData{Score: -1, MinimumScore: test.minimumScore}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if message.String() != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, message.String())
+		}
+	}
+}
+
+func testPull(number int, component string, score float32) *github.PullRequest {
+	p := fake.NewPullRequest(fake.PullRequest{
+		Issue: &githubapi.Issue{HTMLURL: githubapi.String(fmt.Sprintf("https://github.com/openshift/repo-%d/pull/%d", number, number))},
+		Bug:   &tracker.Issue{ID: strconv.Itoa(number), Component: []string{component}},
+	})
+	p.Score = score
+	return p
+}
+
+func TestFairShareSelection(t *testing.T) {
+	capacityConfig := &config.CapacityConfig{
+		MaximumDefaultPicksPerComponent: 10,
+		Allocation:                      config.RoundRobinAllocation,
+		Groups: []config.ComponentGroup{
+			{Name: "Dominant", Capacity: 10, Components: []string{"dominant"}},
+			{Name: "Small", Capacity: 10, Components: []string{"small"}},
+		},
+	}
+	tests := []struct {
+		name      string
+		pulls     []*github.PullRequest
+		budget    int
+		decisions []string
+	}{
+		{
+			name: "dominant group can't take the whole budget",
+			pulls: []*github.PullRequest{
+				testPull(1, "dominant", 0.9), testPull(2, "dominant", 0.8), testPull(3, "dominant", 0.7), testPull(4, "dominant", 0.6),
+				testPull(5, "small", 0.3),
+			},
+			budget:    4,
+			decisions: []string{"pick", "pick", "pick", "skip", "pick"},
+		},
+		{
+			name: "components outside of groups get no share but backfill unused shares",
+			pulls: []*github.PullRequest{
+				testPull(1, "dominant", 0.9), testPull(2, "dominant", 0.8),
+				testPull(3, "other", 0.5), testPull(4, "other", 0.45), testPull(5, "other", 0.4),
+				testPull(6, "small", 0.3),
+			},
+			budget:    4,
+			decisions: []string{"pick", "pick", "pick", "skip", "skip", "pick"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &releaseOptions{
+				release:          "4.7",
+				config:           &config.PatchManagerConfig{CapacityConfig: *capacityConfig},
+				capacity:         capacityConfig,
+				useCapacityCount: test.budget,
+			}
+			costs := make([]float32, len(test.pulls))
+			for i := range costs {
+				costs[i] = 1
+			}
+			result, err := r.fairShareSelection(test.pulls, costs, capacity.NewTracker(capacityConfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			decisions := []string{}
+			for _, s := range result {
+				decisions = append(decisions, s.decision)
+			}
+			if !reflect.DeepEqual(decisions, test.decisions) {
+				t.Errorf("expected %v, got %v", test.decisions, decisions)
+			}
+		})
+	}
+}
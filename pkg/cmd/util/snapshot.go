@@ -0,0 +1,62 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+)
+
+// NewSnapshotStore returns snapshot store based on the --record and --replay flags or nil when none of them is set.
+func NewSnapshotStore(recordDir, replayDir string) (*snapshot.Store, error) {
+	switch {
+	case len(recordDir) > 0 && len(replayDir) > 0:
+		return nil, fmt.Errorf("only one of --record and --replay can be specified")
+	case len(recordDir) > 0:
+		return snapshot.NewRecorder(recordDir)
+	case len(replayDir) > 0:
+		return snapshot.NewReplayer(replayDir)
+	default:
+		return nil, nil
+	}
+}
+
+// ConfigFile returns the config file to use. When replaying it is the config recorded in the snapshot unless the
+// --config flag was set explicitly, as its default comes from the PATCHMANAGER_CONFIG env variable that is usually set.
+func ConfigFile(store *snapshot.Store, fs *pflag.FlagSet, configFile string) string {
+	if store.Replaying() && (fs == nil || !fs.Changed("config")) {
+		return store.Path(snapshot.ConfigFile)
+	}
+	return configFile
+}
+
+// RecordConfig saves the config used for the run into the snapshot, so the run can be replayed with the same config and release.
+func RecordConfig(store *snapshot.Store, c *config.PatchManagerConfig, release string) error {
+	if !store.Recording() {
+		return nil
+	}
+	recorded := *c
+	recorded.Release = release
+	content, err := yaml.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	return store.SaveConfig(content)
+}
+
+// RecordReleaseConfig saves the config of a single release in a multi-release run into the snapshot.
+func RecordReleaseConfig(store *snapshot.Store, c *config.PatchManagerConfig, release string) error {
+	if !store.Recording() {
+		return nil
+	}
+	recorded := *c
+	recorded.Release = release
+	content, err := yaml.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	return store.SaveReleaseConfig(release, content)
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/patchmanager/pkg/snapshot"
+)
+
+func TestConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	replayer, err := snapshot.NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder, err := snapshot.NewRecorder(filepath.Join(dir, "record"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		store    *snapshot.Store
+		args     []string
+		expected string
+	}{
+		{
+			name:     "no snapshot",
+			expected: "/env/config.yaml",
+		},
+		{
+			name:     "recording",
+			store:    recorder,
+			expected: "/env/config.yaml",
+		},
+		{
+			name:     "replay ignores the config from env",
+			store:    replayer,
+			expected: filepath.Join(dir, snapshot.ConfigFile),
+		},
+		{
+			name:     "replay with explicit config",
+			store:    replayer,
+			args:     []string{"--config=other.yaml"},
+			expected: "other.yaml",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var configFile string
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			// default as set from PATCHMANAGER_CONFIG env variable
+			fs.StringVar(&configFile, "config", "/env/config.yaml", "")
+			if err := fs.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+			if result := ConfigFile(test.store, fs, configFile); result != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, result)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"crypto/tls"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -43,15 +44,77 @@ func GetConfig(location string) (*PatchManagerConfig, error) {
 	return &config, err
 }
 
-func ComponentCapacity(config *CapacityConfig, name string) (bool, int) {
-	for _, group := range config.Groups {
-		for _, c := range group.Components {
-			if c == name {
-				return true, group.Capacity
+// DefaultSearchConfig is used for fields that are not set in the search configuration.
+var DefaultSearchConfig = SearchConfig{
+	Orgs:          []string{"kube-reporting", "openshift", "operator-framework"},
+	ExcludeRepos:  &[]string{"openshift/openshift-docs"},
+	RequireLabels: []string{"lgtm", "approved", "bugzilla/valid-bug,jira/valid-bug"},
+	Branches:      []string{"release-{release}", "openshift-{release}", "enterprise-{release}"},
+}
+
+// SearchWithDefaults returns search configuration with empty fields set to the DefaultSearchConfig values.
+func SearchWithDefaults(c SearchConfig) SearchConfig {
+	if len(c.Orgs) == 0 && len(c.IncludeRepos) == 0 {
+		c.Orgs = DefaultSearchConfig.Orgs
+	}
+	if c.ExcludeRepos == nil {
+		c.ExcludeRepos = DefaultSearchConfig.ExcludeRepos
+	}
+	if len(c.RequireLabels) == 0 {
+		c.RequireLabels = DefaultSearchConfig.RequireLabels
+	}
+	if len(c.Branches) == 0 {
+		c.Branches = DefaultSearchConfig.Branches
+	}
+	return c
+}
+
+// ValidateCapacity returns error when the capacity selection or allocation is unknown or they can't be combined, as
+// the fair share allocation is done by the greedy selection only.
+func ValidateCapacity(c *CapacityConfig) error {
+	switch c.Selection {
+	case "", GreedySelection, OptimalSelection:
+	default:
+		return fmt.Errorf("unknown capacity selection %q (supported: %s, %s)", c.Selection, GreedySelection, OptimalSelection)
+	}
+	switch c.Allocation {
+	case "", ScoreAllocation:
+	case RoundRobinAllocation, WeightedAllocation:
+		if c.Selection == OptimalSelection {
+			return fmt.Errorf("capacity allocation %q can't be used with optimal selection", c.Allocation)
+		}
+	default:
+		return fmt.Errorf("unknown capacity allocation %q (supported: %s, %s, %s)", c.Allocation, ScoreAllocation,
+			RoundRobinAllocation, WeightedAllocation)
+	}
+	return nil
+}
+
+// ComponentGroupFor returns the capacity group the component belongs to or nil if the component is not in any group.
+func ComponentGroupFor(config *CapacityConfig, name string) *ComponentGroup {
+	for i := range config.Groups {
+		for _, c := range config.Groups[i].Components {
+			if strings.EqualFold(c, name) {
+				return &config.Groups[i]
 			}
 		}
 	}
-	return false, config.MaximumDefaultPicksPerComponent
+	return nil
+}
+
+// ComponentLimit returns the limit of picks of the component within the group capacity, if the group set one.
+func ComponentLimit(group *ComponentGroup, name string) (bool, int) {
+	for c, limit := range group.ComponentLimits {
+		if strings.EqualFold(c, name) {
+			return true, limit
+		}
+	}
+	return false, 0
+}
+
+// HasCost returns true when the cost of pull requests is configured, otherwise every pull request takes 1 point.
+func HasCost(c CostConfig) bool {
+	return c.Base != 0 || c.PerHundredLines != 0 || len(c.Severities) > 0 || len(c.Repos) > 0 || len(c.Components) > 0
 }
 
 func HasMergeWindow(c MergeWindowConfig) bool {
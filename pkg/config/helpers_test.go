@@ -0,0 +1,97 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestSearchWithDefaultsExcludeRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected []string
+	}{
+		{
+			name:     "default",
+			config:   "search:\n  orgs: [openshift]\n",
+			expected: []string{"openshift/openshift-docs"},
+		},
+		{
+			name:     "explicit empty",
+			config:   "search:\n  excludeRepos: []\n",
+			expected: []string{},
+		},
+		{
+			name:     "explicit list",
+			config:   "search:\n  excludeRepos: [openshift/origin]\n",
+			expected: []string{"openshift/origin"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var loaded PatchManagerConfig
+			if err := yaml.Unmarshal([]byte(test.config), &loaded); err != nil {
+				t.Fatal(err)
+			}
+			// the setting must survive saving the loaded config (eg. into a snapshot)
+			saved, err := yaml.Marshal(loaded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reloaded PatchManagerConfig
+			if err := yaml.Unmarshal(saved, &reloaded); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []PatchManagerConfig{loaded, reloaded} {
+				search := SearchWithDefaults(c.SearchConfig)
+				if search.ExcludeRepos == nil || !reflect.DeepEqual(*search.ExcludeRepos, test.expected) {
+					t.Errorf("expected excluded repos %v, got %v", test.expected, search.ExcludeRepos)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCapacity(t *testing.T) {
+	tests := []struct {
+		selection  string
+		allocation string
+		valid      bool
+	}{
+		{valid: true},
+		{selection: GreedySelection, allocation: RoundRobinAllocation, valid: true},
+		{allocation: WeightedAllocation, valid: true},
+		{selection: OptimalSelection, allocation: ScoreAllocation, valid: true},
+		{selection: OptimalSelection, allocation: RoundRobinAllocation},
+		{selection: OptimalSelection, allocation: WeightedAllocation},
+		{selection: "best"},
+		{allocation: "fair"},
+	}
+	for _, test := range tests {
+		err := ValidateCapacity(&CapacityConfig{Selection: test.selection, Allocation: test.allocation})
+		if (err == nil) != test.valid {
+			t.Errorf("selection %q, allocation %q: expected valid %v, got %v", test.selection, test.allocation, test.valid, err)
+		}
+	}
+}
+
+func TestHasCost(t *testing.T) {
+	tests := []struct {
+		config   CostConfig
+		expected bool
+	}{
+		{},
+		{config: CostConfig{Base: 1}, expected: true},
+		{config: CostConfig{PerHundredLines: 0.5}, expected: true},
+		{config: CostConfig{Severities: map[string]float32{"urgent": 2}}, expected: true},
+		{config: CostConfig{Repos: map[string]float32{"openshift/kubernetes": 2}}, expected: true},
+		{config: CostConfig{Components: map[string]float32{"Node": 2}}, expected: true},
+	}
+	for _, test := range tests {
+		if result := HasCost(test.config); result != test.expected {
+			t.Errorf("%+v: expected %v, got %v", test.config, test.expected, result)
+		}
+	}
+}
@@ -6,13 +6,66 @@ type PatchManagerConfig struct {
 	ClassifiersConfigs ClassifierConfig  `yaml:"classifiers"`
 	RulesConfig        RulesConfig       `yaml:"rules"`
 	MergeWindowConfig  MergeWindowConfig `yaml:"mergeWindow"`
+	SearchConfig       SearchConfig      `yaml:"search"`
+	ThresholdsConfig   ThresholdsConfig  `yaml:"thresholds"`
+	// Priority orders releases triaged in a single run, releases with higher priority pick from the shared capacity first.
+	Priority int `yaml:"priority,omitempty"`
+	// ReleasePriorities sets the priority of releases triaged in a single run without release configs, it takes precedence
+	// over the priority of release configs.
+	ReleasePriorities map[string]int `yaml:"releasePriorities,omitempty"`
+}
+
+// ThresholdsConfig describe score thresholds that decide about picking pull requests regardless of their order.
+type ThresholdsConfig struct {
+	// MinimumScore is the lowest score of pull request that can be picked (default: 0).
+	MinimumScore float32 `yaml:"minScore"`
+	// SkipMessage is a Go template of the decision reason for pull requests with score lower than MinimumScore.
+	// Available fields: .Score, .MinimumScore, .URL and .Component.
+	SkipMessage string `yaml:"skipMessage,omitempty"`
+	// MustPickScore is the score above which pull requests are picked even when their component capacity is exhausted.
+	// The total capacity still applies. Not set means no pull request is picked over the component capacity.
+	MustPickScore *float32 `yaml:"mustPickScore,omitempty"`
+}
+
+// SearchConfig describe the Github search query used to find z-stream candidate pull requests.
+// Empty fields fall back to the values in DefaultSearchConfig.
+type SearchConfig struct {
+	// Orgs lists Github organizations to search pull requests in.
+	Orgs []string `yaml:"orgs,omitempty"`
+	// IncludeRepos lists additional repositories (org/repo) to search outside of Orgs.
+	IncludeRepos []string `yaml:"includeRepos,omitempty"`
+	// ExcludeRepos lists repositories (org/repo) that are never searched. Set to empty list to not exclude any repository,
+	// not set (nil) means the default list. It is a pointer, so the empty list survives saving the config (eg. into a snapshot).
+	ExcludeRepos *[]string `yaml:"excludeRepos,omitempty"`
+	// RequireLabels lists labels every candidate must have. Comma separated labels (eg. "a,b") match any of them.
+	RequireLabels []string `yaml:"requireLabels,omitempty"`
+	// Branches lists base branch name patterns where "{release}" is replaced with the target release (eg. "release-{release}").
+	Branches []string `yaml:"branches,omitempty"`
 }
 
 type ClassifierConfig struct {
-	KeywordsClassifier  KeywordsClassifierConfig  `yaml:"keywords"`
-	ComponentClassifier ComponentClassifierConfig `yaml:"components"`
-	Severities          SeverityClassifierConfig  `yaml:"severities"`
-	PMScores            PMScoreClassifierConfig   `yaml:"pmScores"`
+	KeywordsClassifier  KeywordsClassifierConfig    `yaml:"keywords"`
+	ComponentClassifier ComponentClassifierConfig   `yaml:"components"`
+	Severities          SeverityClassifierConfig    `yaml:"severities"`
+	Priorities          PriorityClassifierConfig    `yaml:"priorities"`
+	Escalation          EscalationClassifierConfig  `yaml:"escalation"`
+	Flags               FlagsClassifierConfig       `yaml:"flags"`
+	PMScores            PMScoreClassifierConfig     `yaml:"pmScores"`
+	UpstreamFix         UpstreamFixClassifierConfig `yaml:"upstreamFix"`
+	Risk                RiskClassifierConfig        `yaml:"risk"`
+	Expressions         ExpressionClassifierConfig  `yaml:"expressions"`
+
+	// Enabled lists classifiers (by name) used to score pull requests. Empty list enables all classifiers with weight 1.
+	Enabled []EnabledClassifier `yaml:"enabled,omitempty"`
+	// Combine is the way the weighted classifier scores are combined: "sum" (default), "max" or "product".
+	Combine string `yaml:"combine,omitempty"`
+}
+
+// EnabledClassifier is a classifier name (eg. "severity") with the multiplier of its score.
+type EnabledClassifier struct {
+	Name string `yaml:"name"`
+	// Weight multiplies the classifier score (default: 1).
+	Weight *float32 `yaml:"weight,omitempty"`
 }
 
 type MergeWindowConfig struct {
@@ -22,6 +75,11 @@ type MergeWindowConfig struct {
 
 type RulesConfig struct {
 	PullRequestLabelConfig PullRequestLabelRuleConfig `yaml:"labels"`
+	BugFlagsConfig         BugFlagsRuleConfig         `yaml:"bugFlags"`
+	BugStateConfig         BugStateRuleConfig         `yaml:"bugState"`
+	UpstreamFixConfig      UpstreamFixRuleConfig      `yaml:"upstreamFix"`
+	BackportChainConfig    BackportChainRuleConfig    `yaml:"backportChain"`
+	ExpressionRules        []ExpressionRuleConfig     `yaml:"expressions"`
 }
 
 type PullRequestLabelRuleConfig struct {
@@ -29,11 +87,101 @@ type PullRequestLabelRuleConfig struct {
 	RequireLabel  []string `yaml:"require"`
 }
 
+// BugFlagsRuleConfig lists bug flags including their status (eg. "blocker+", "backport-")
+type BugFlagsRuleConfig struct {
+	RefuseOnFlag []string `yaml:"refuse"`
+	RequireFlag  []string `yaml:"require"`
+}
+
+// BugStateRuleConfig describe the bug status and target release a pull request bug must have.
+type BugStateRuleConfig struct {
+	// AllowedStatuses lists bug statuses (eg. POST, MODIFIED). Empty list allows all statuses.
+	AllowedStatuses []string `yaml:"allowedStatuses"`
+	// TargetRelease is the expected bug target release where "{release}" is replaced by the triaged release (eg. "{release}.z").
+	// Empty value disables the target release check.
+	TargetRelease string `yaml:"targetRelease"`
+}
+
+// UpstreamFixRuleConfig describe the check of the parent bug (the bug the backport was cloned from).
+type UpstreamFixRuleConfig struct {
+	// Required refuse pull requests with parent bug which fix was not verified yet.
+	Required bool `yaml:"required"`
+	// VerifiedStatuses lists statuses of verified parent bugs (default: VERIFIED, CLOSED ERRATA).
+	VerifiedStatuses []string `yaml:"verifiedStatuses,omitempty"`
+}
+
+// BackportChainRuleConfig describe the check of the fix being merged in all releases newer than the triaged release.
+type BackportChainRuleConfig struct {
+	// Required refuse pull requests which fix is missing or not merged in a newer release.
+	Required bool `yaml:"required"`
+	// AcceptApproved treats pull requests approved for the newer z-stream (cherry-pick-approved label) as merged.
+	AcceptApproved bool `yaml:"acceptApproved"`
+}
+
+// ExpressionRuleConfig describe rule that refuse pull requests matching CEL expression.
+type ExpressionRuleConfig struct {
+	// Name identifies the rule verdict.
+	Name string `yaml:"name"`
+	// Expression is a boolean CEL expression (eg. `repo == "openshift/installer" && severity == "low"`), pull requests
+	// for which it is true are refused.
+	Expression string `yaml:"expression"`
+	// Message is a Go template of the decision reason, the expression variables are available (eg. "{{.repo}}").
+	Message string `yaml:"message"`
+}
+
 type KeywordsClassifierConfig map[string]float32
+type FlagsClassifierConfig map[string]float32
 type ComponentClassifierConfig map[string]float32
 type SeverityClassifierConfig map[string]float32
+type PriorityClassifierConfig map[string]float32
 type PMScoreClassifierConfig []PMScoreRange
 
+// EscalationClassifierConfig describe score for bugs with customer impact.
+type EscalationClassifierConfig struct {
+	// Escalated is the score for bugs flagged as customer facing escalation.
+	Escalated float32 `yaml:"escalated"`
+	// PerCase is the score for every customer case linked to the bug.
+	PerCase float32 `yaml:"perCase"`
+	// MaxCases caps the number of customer cases that are scored (0 means no cap).
+	MaxCases int `yaml:"maxCases"`
+}
+
+// UpstreamFixClassifierConfig describe score for pull requests which parent bug fix was not verified yet.
+type UpstreamFixClassifierConfig struct {
+	// NotVerified is the score (usually negative) for pull requests with parent bug not verified or not accessible.
+	NotVerified float32 `yaml:"notVerified"`
+	// VerifiedStatuses lists statuses of verified parent bugs (default: VERIFIED, CLOSED ERRATA).
+	VerifiedStatuses []string `yaml:"verifiedStatuses,omitempty"`
+}
+
+// RiskClassifierConfig describe score (usually negative) for pull requests with large or risky code changes.
+type RiskClassifierConfig struct {
+	// PerHundredLines is the score for every 100 lines changed (additions and deletions).
+	PerHundredLines float32 `yaml:"perHundredLines"`
+	// PerFile is the score for every changed file.
+	PerFile float32 `yaml:"perFile"`
+	// HighRiskPaths lists path globs with the score for pull requests changing any matching file.
+	HighRiskPaths []RiskPath `yaml:"highRiskPaths"`
+	// MinScore caps the risk score so huge pull requests are not pushed too far down (0 means no cap).
+	MinScore float32 `yaml:"minScore"`
+}
+
+// RiskPath is a glob (eg. "*.crd.yaml") matching the changed file path or its base name. Globs ending with "/" (eg. "vendor/")
+// match all files in directory with that name.
+type RiskPath struct {
+	Glob  string  `yaml:"glob"`
+	Score float32 `yaml:"score"`
+}
+
+// ExpressionClassifierConfig lists CEL expressions with the score for pull requests matching them.
+type ExpressionClassifierConfig []ExpressionScore
+
+// ExpressionScore is a boolean CEL expression (eg. `whiteboard.contains("UpgradeBlocker")`) and the score added when it is true.
+type ExpressionScore struct {
+	Expression string  `yaml:"expression"`
+	Score      float32 `yaml:"score"`
+}
+
 type PMScoreRange struct {
 	From  int     `yaml:"from"`
 	To    int     `yaml:"to"`
@@ -49,10 +197,59 @@ type CapacityConfig struct {
 
 	// MaximumdefaultPicksPerComponent is default capacity for component when there is no capacity defined.
 	MaximumDefaultPicksPerComponent int `yaml:"maxDefaultPicksPerComponent"`
+
+	// MaximumPicksPerRepo limits number of pull requests approved in single repository (0 means no limit).
+	MaximumPicksPerRepo int `yaml:"maxPicksPerRepo,omitempty"`
+
+	// Selection is the way pull requests are picked: "greedy" (default) picks in the score order until the capacity is
+	// exhausted, "optimal" picks pull requests with the highest total score that fit into the capacity.
+	Selection string `yaml:"selection,omitempty"`
+
+	// Allocation is the way the total capacity is divided among the groups by the greedy selection: "score" (default)
+	// picks in the score order only, "roundRobin" gives every group the same share and "weighted" gives every group share
+	// proportional to its weight. Groups pick their best pull requests within their share first, the unused share is then
+	// filled in the score order. Components outside of any group get no share.
+	Allocation string `yaml:"allocation,omitempty"`
+
+	// Cost describe how many capacity points every pull request takes. When not set, every pull request takes 1 point,
+	// so all capacities are number of picks.
+	Cost CostConfig `yaml:"cost,omitempty"`
 }
 
+// CostConfig describe the capacity points of a pull request:
+// (base + perHundredLines * changed lines / 100) * severity factor * repository factor * component factor
+type CostConfig struct {
+	// Base is the cost of every pull request (default: 1).
+	Base float32 `yaml:"base,omitempty"`
+	// PerHundredLines is added for every 100 lines changed (additions and deletions).
+	PerHundredLines float32 `yaml:"perHundredLines,omitempty"`
+	// Severities lists cost factors of bug severities (default factor is 1).
+	Severities map[string]float32 `yaml:"severities,omitempty"`
+	// Repos lists cost factors of repositories (org/repo).
+	Repos map[string]float32 `yaml:"repos,omitempty"`
+	// Components lists cost factors of components.
+	Components map[string]float32 `yaml:"components,omitempty"`
+}
+
+const (
+	GreedySelection  = "greedy"
+	OptimalSelection = "optimal"
+)
+
+const (
+	ScoreAllocation      = "score"
+	RoundRobinAllocation = "roundRobin"
+	WeightedAllocation   = "weighted"
+)
+
+// ComponentGroup is a set of components sharing the QE capacity.
 type ComponentGroup struct {
-	Name       string   `yaml:"name"`
+	Name string `yaml:"name"`
+	// Capacity is the maximum number of picks for all components in the group together.
 	Capacity   int      `yaml:"capacity"`
 	Components []string `yaml:"components"`
+	// ComponentLimits optionally limits the number of picks of single component within the group capacity.
+	ComponentLimits map[string]int `yaml:"componentLimits,omitempty"`
+	// Weight is the relative share of the total capacity with the "weighted" allocation (default: the group capacity).
+	Weight int `yaml:"weight,omitempty"`
 }
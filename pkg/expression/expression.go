@@ -0,0 +1,116 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// variables lists the names and types of variables available in expressions.
+var variables = []cel.EnvOption{
+	// pull request
+	cel.Variable("repo", cel.StringType),  // org/repo
+	cel.Variable("title", cel.StringType), // pull request title
+	cel.Variable("url", cel.StringType),
+	cel.Variable("labels", cel.ListType(cel.StringType)),
+	cel.Variable("age", cel.DurationType), // time since the pull request was created until the run
+	cel.Variable("ageDays", cel.IntType),
+	// bug
+	cel.Variable("bugID", cel.StringType),
+	cel.Variable("severity", cel.StringType),
+	cel.Variable("priority", cel.StringType),
+	cel.Variable("status", cel.StringType),
+	cel.Variable("keywords", cel.ListType(cel.StringType)),
+	cel.Variable("component", cel.StringType), // lowercase, subcomponents separated by "/"
+	cel.Variable("whiteboard", cel.StringType),
+	cel.Variable("flags", cel.ListType(cel.StringType)),
+	cel.Variable("targetRelease", cel.ListType(cel.StringType)),
+	cel.Variable("escalated", cel.BoolType),
+	cel.Variable("customerCases", cel.IntType),
+}
+
+// Expression is a compiled CEL expression evaluated against the pull request and its bug.
+type Expression struct {
+	source  string
+	program cel.Program
+}
+
+// Compile parse and type check the expression, the expression must return boolean.
+func Compile(source string) (*Expression, error) {
+	env, err := cel.NewEnv(variables...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", source, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression %q must return bool, not %s", source, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", source, err)
+	}
+	return &Expression{source: source, program: program}, nil
+}
+
+// String returns the expression source.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate returns the expression result for the pull request, now is the time of the run the pull request age is
+// computed against. The pull request bug must be fetched.
+func (e *Expression) Evaluate(pullRequest *github.PullRequest, now time.Time) (bool, error) {
+	out, _, err := e.program.Eval(Variables(pullRequest, now))
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate %q: %v", e.source, err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returned %v, expected bool", e.source, out.Value())
+	}
+	return result, nil
+}
+
+// Variables returns the view of the pull request and its bug the expressions are evaluated against at given time of the run.
+func Variables(pullRequest *github.PullRequest, now time.Time) map[string]interface{} {
+	owner, repo := github.GetPullMetaFromURL(pullRequest.Issue.GetHTMLURL())
+	labels := []string{}
+	for _, l := range pullRequest.Issue.Labels {
+		labels = append(labels, l.GetName())
+	}
+	age := now.Sub(pullRequest.Issue.GetCreatedAt())
+	bug := pullRequest.Bug()
+	return map[string]interface{}{
+		"repo":          owner + "/" + repo,
+		"title":         pullRequest.Issue.GetTitle(),
+		"url":           pullRequest.Issue.GetHTMLURL(),
+		"labels":        labels,
+		"age":           age,
+		"ageDays":       int64(age / (24 * time.Hour)),
+		"bugID":         pullRequest.BugID(),
+		"severity":      bug.Severity,
+		"priority":      bug.Priority,
+		"status":        bug.Status,
+		"keywords":      nonNil(bug.Keywords),
+		"component":     strings.ToLower(strings.Join(bug.Component, "/")),
+		"whiteboard":    bug.Whiteboard,
+		"flags":         nonNil(bug.Flags),
+		"targetRelease": nonNil(bug.TargetRelease),
+		"escalated":     bug.Escalated,
+		"customerCases": int64(len(bug.CustomerCases)),
+	}
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,105 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+var now = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testPullRequest() *github.PullRequest {
+	created := now.Add(-(10*24 + 5) * time.Hour)
+	return fake.NewPullRequest(fake.PullRequest{
+		Issue: &githubapi.Issue{
+			Title:     githubapi.String("Bug 1234: fix the operator status"),
+			HTMLURL:   githubapi.String("https://github.com/openshift/installer/pull/42"),
+			Labels:    []*githubapi.Label{{Name: githubapi.String("lgtm")}, {Name: githubapi.String("approved")}},
+			CreatedAt: &created,
+		},
+		Bug: &tracker.Issue{
+			ID:            "1234",
+			Status:        "POST",
+			Severity:      "high",
+			Priority:      "medium",
+			Keywords:      []string{"Regression"},
+			Component:     []string{"Networking", "ovn-kubernetes"},
+			Whiteboard:    "SDN",
+			TargetRelease: []string{"4.7.z"},
+			Escalated:     true,
+			CustomerCases: []string{"0001", "0002"},
+		},
+	})
+}
+
+func TestVariables(t *testing.T) {
+	expected := map[string]interface{}{
+		"repo":          "openshift/installer",
+		"title":         "Bug 1234: fix the operator status",
+		"url":           "https://github.com/openshift/installer/pull/42",
+		"labels":        []string{"lgtm", "approved"},
+		"age":           (10*24 + 5) * time.Hour,
+		"ageDays":       int64(10),
+		"bugID":         "1234",
+		"severity":      "high",
+		"priority":      "medium",
+		"status":        "POST",
+		"keywords":      []string{"Regression"},
+		"component":     "networking/ovn-kubernetes",
+		"whiteboard":    "SDN",
+		"flags":         []string{},
+		"targetRelease": []string{"4.7.z"},
+		"escalated":     true,
+		"customerCases": int64(2),
+	}
+	if variables := Variables(testPullRequest(), now); !reflect.DeepEqual(variables, expected) {
+		t.Errorf("expected %#v, got %#v", expected, variables)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		now        time.Time
+		expected   bool
+	}{
+		{expression: `repo == "openshift/installer" && severity == "high"`, now: now, expected: true},
+		{expression: `"Regression" in keywords && customerCases > 1`, now: now, expected: true},
+		{expression: `component.startsWith("networking/")`, now: now, expected: true},
+		{expression: `"blocker+" in flags`, now: now},
+		{expression: `ageDays > 10`, now: now},
+		{expression: `ageDays > 10`, now: now.Add(24 * time.Hour), expected: true},
+		{expression: `age > duration("240h")`, now: now, expected: true},
+	}
+	for _, test := range tests {
+		e, err := Compile(test.expression)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.expression, err)
+		}
+		result, err := e.Evaluate(testPullRequest(), test.now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.expression, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s at %s: expected %v, got %v", test.expression, test.now, test.expected, result)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{
+		`severity ==`,
+		`unknown == "high"`,
+		`severity`,
+	} {
+		if _, err := Compile(source); err == nil {
+			t.Errorf("%s: expected error", source)
+		}
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"strconv"
 	"strings"
 
-	"golang.org/x/oauth2"
-
 	"github.com/google/go-github/v32/github"
 )
 
@@ -16,7 +14,7 @@ type PullRequestApprover struct {
 }
 
 func NewPullRequestApprover(ctx context.Context, ghToken string) *PullRequestApprover {
-	return &PullRequestApprover{client: github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: ghToken})))}
+	return &PullRequestApprover{client: newGithubClient(ctx, ghToken)}
 }
 
 func (p *PullRequestApprover) CherryPickApprove(ctx context.Context, url string) error {
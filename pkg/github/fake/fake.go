@@ -0,0 +1,63 @@
+// Package fake provides pull requests served from memory, to test classifiers and rules without accessing Github or
+// issue trackers.
+package fake
+
+import (
+	"fmt"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/github"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+// PullRequest describe pull request with its bug, related bugs and pull requests served from memory instead of being
+// fetched.
+type PullRequest struct {
+	Issue *githubapi.Issue
+	// Bug is the bug referenced in the pull request title, BugErr is returned when it is not set
+	Bug    *tracker.Issue
+	BugErr error
+	// RelatedBugs and RelatedPulls (by URL) are returned by RelatedBug and RelatedPullRequest, Details returns the
+	// related pull request with the pull request URL
+	RelatedBugs  map[string]*tracker.Issue
+	RelatedPulls map[string]*githubapi.PullRequest
+	Files        []*githubapi.CommitFile
+}
+
+// NewPullRequest returns pull request serving the fake data.
+func NewPullRequest(f PullRequest) *github.PullRequest {
+	if f.Issue == nil {
+		f.Issue = &githubapi.Issue{}
+	}
+	var bugTracker, bugID string
+	if f.Bug != nil {
+		bugTracker, bugID = f.Bug.Tracker, f.Bug.ID
+	}
+	return github.NewPullRequest(f.Issue, bugTracker, bugID, github.Fetchers{
+		Bug: func(id string) (*tracker.Issue, error) {
+			if f.Bug == nil {
+				if f.BugErr == nil {
+					return nil, fmt.Errorf("bug %s not found", id)
+				}
+				return nil, f.BugErr
+			}
+			return f.Bug, nil
+		},
+		RelatedBug: func(id string) (*tracker.Issue, error) {
+			if bug, ok := f.RelatedBugs[id]; ok {
+				return bug, nil
+			}
+			return nil, fmt.Errorf("bug %s not found", id)
+		},
+		RelatedPull: func(prURL string) (*githubapi.PullRequest, error) {
+			if pull, ok := f.RelatedPulls[prURL]; ok {
+				return pull, nil
+			}
+			return nil, fmt.Errorf("pull request %s not found", prURL)
+		},
+		Files: func() ([]*githubapi.CommitFile, error) {
+			return f.Files, nil
+		},
+	})
+}
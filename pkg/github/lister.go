@@ -4,30 +4,76 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
-
-	"github.com/eparis/bugzilla"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v32/github"
-	"golang.org/x/oauth2"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+	"github.com/openshift/patchmanager/pkg/tracker"
 )
 
-const queryTemplate = "org:kube-reporting org:openshift org:operator-framework label:lgtm label:approved label:bugzilla/valid-bug " +
-	"base:release-%[1]s base:openshift-%[1]s base:enterprise-%[1]s is:open -repo:openshift/openshift-docs"
+// bugFetchBackoff is used to retry failed bug fetches
+var bugFetchBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    4,
+}
 
 type PullRequestLister struct {
 	ghClient *github.Client
-	bzClient bugzilla.Client
+	trackers tracker.Trackers
+	search   config.SearchConfig
+	snapshot *snapshot.Store
+
+	bugCache     map[string]*bugCacheEntry
+	bugCacheLock sync.Mutex
+
+	pullCache     map[string]*pullCacheEntry
+	pullCacheLock sync.Mutex
 }
 
-func NewPullRequestLister(ctx context.Context, ghToken string, bzToken string) *PullRequestLister {
+type bugCacheEntry struct {
+	once sync.Once
+	bug  *tracker.Issue
+	err  error
+}
+
+type pullCacheEntry struct {
+	once sync.Once
+	pull *github.PullRequest
+	err  error
+}
+
+func NewPullRequestLister(ctx context.Context, ghToken string, bzToken string, jiraToken string, search config.SearchConfig) *PullRequestLister {
 	return &PullRequestLister{
-		bzClient: bugzilla.NewClient(func() []byte {
-			return []byte(bzToken)
-		}, "https://bugzilla.redhat.com"),
-		ghClient: github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: ghToken}))),
+		search:    config.SearchWithDefaults(search),
+		bugCache:  map[string]*bugCacheEntry{},
+		pullCache: map[string]*pullCacheEntry{},
+		trackers: tracker.NewTrackers(
+			tracker.NewBugzillaTracker(bzToken),
+			tracker.NewJiraTracker(tracker.JiraEndpoint, jiraToken),
+		),
+		ghClient: newGithubClient(ctx, ghToken),
+	}
+}
+
+// WithSnapshot make the lister record everything it fetch into the snapshot store or, in replay mode, serve everything from
+// the store without accessing Github or issue trackers.
+func (l *PullRequestLister) WithSnapshot(store *snapshot.Store) *PullRequestLister {
+	if store == nil {
+		return l
+	}
+	l.snapshot = store
+	for name := range l.trackers {
+		l.trackers[name] = snapshot.WrapTracker(store, l.trackers[name])
 	}
+	return l
 }
 
 func GetPullMetaFromURL(prURL string) (string, string) {
@@ -37,70 +83,291 @@ func GetPullMetaFromURL(prURL string) (string, string) {
 
 func (l *PullRequestLister) GetPullRequestStatus(ctx context.Context, p *PullRequest) ([]*github.RepoStatus, error) {
 	owner, repo := GetPullMetaFromURL(p.Issue.GetHTMLURL())
+	key := fmt.Sprintf("%s/%s/%d", owner, repo, p.Issue.GetNumber())
+	var statuses []*github.RepoStatus
+	if l.snapshot.Replaying() {
+		return statuses, l.snapshot.Load(snapshot.StatusKind, key, &statuses)
+	}
 	pr, _, err := l.ghClient.PullRequests.Get(ctx, owner, repo, p.Issue.GetNumber())
 	if err != nil {
 		return nil, err
 	}
 	prHeadSHA := pr.GetHead().GetSHA()
-	statuses, _, err := l.ghClient.Repositories.ListStatuses(ctx, owner, repo, prHeadSHA, &github.ListOptions{})
+	statuses, _, err = l.ghClient.Repositories.ListStatuses(ctx, owner, repo, prHeadSHA, &github.ListOptions{})
+	if err == nil && l.snapshot.Recording() {
+		err = l.snapshot.Save(snapshot.StatusKind, key, statuses)
+	}
 	return statuses, err
 }
 
-func (l *PullRequestLister) ListForRelease(ctx context.Context, release, labels string) ([]*PullRequest, error) {
-	query := buildGithubSearchQuery(queryTemplate, release) + " " + labels
-	result, _, err := l.ghClient.Search.Issues(ctx, query, &github.SearchOptions{Sort: "updated", ListOptions: github.ListOptions{PerPage: 150}})
+// ListForRelease returns pull requests for the release matching the labels query together with the Github search stats.
+func (l *PullRequestLister) ListForRelease(ctx context.Context, release, labels string) ([]*PullRequest, SearchStats, error) {
+	issues, stats, err := l.searchAll(ctx, l.search, release, labels)
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
 	var pullRequests []*PullRequest
-	for i := range result.Issues {
-		if !result.Issues[i].IsPullRequest() {
+	for i := range issues {
+		if !issues[i].IsPullRequest() {
 			continue
 		}
-
-		newPullRequest := &PullRequest{
-			Issue: result.Issues[i],
-			Score: 0,
+		newPullRequest, err := l.newPullRequest(ctx, issues[i])
+		if err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+			continue
 		}
-		bugNumber := parseBugNumber(newPullRequest.Issue.GetTitle())
+		pullRequests = append(pullRequests, newPullRequest)
+	}
 
-		if newPullRequest.bugID, err = strconv.Atoi(bugNumber); len(bugNumber) == 0 || err != nil {
-			fmt.Printf("WARNING: Pull Request with invalid title: %s: %s (%v)\n", newPullRequest.Issue.GetHTMLURL(), newPullRequest.Issue.GetTitle(), err)
-			continue
+	return pullRequests, stats, nil
+}
+
+// GetPullRequest returns a single pull request by its URL (eg. https://github.com/openshift/origin/pull/1234).
+func (l *PullRequestLister) GetPullRequest(ctx context.Context, prURL string) (*PullRequest, error) {
+	owner, repo, number, err := parsePullRequestMeta(prURL)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s/%s/%d", owner, repo, number)
+	issue := &github.Issue{}
+	if l.snapshot.Replaying() {
+		if err := l.snapshot.Load(snapshot.IssueKind, key, issue); err != nil {
+			return nil, err
 		}
-		newPullRequest.getBugFn = func(id int) *bugzilla.Bug {
-			bz, err := l.bzClient.GetBug(id)
+		return l.newPullRequest(ctx, issue)
+	}
+	issue, _, err = l.ghClient.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	if !issue.IsPullRequest() {
+		return nil, fmt.Errorf("%s is not a pull request", prURL)
+	}
+	if l.snapshot.Recording() {
+		if err := l.snapshot.Save(snapshot.IssueKind, key, issue); err != nil {
+			return nil, err
+		}
+	}
+	return l.newPullRequest(ctx, issue)
+}
+
+func (l *PullRequestLister) newPullRequest(ctx context.Context, issue *github.Issue) (*PullRequest, error) {
+	trackerName, bugID := parseBugReference(issue.GetTitle())
+	if len(bugID) == 0 {
+		return nil, fmt.Errorf("pull request with invalid title: %s: %s", issue.GetHTMLURL(), issue.GetTitle())
+	}
+	return NewPullRequest(issue, trackerName, bugID, Fetchers{
+		// pull requests sharing the bug fetch it only once
+		Bug: func(id string) (*tracker.Issue, error) {
+			bug, err := l.GetBug(trackerName, id)
 			if err != nil {
-				fmt.Printf("Failed to fetch bug #%d for %s: %s\n", id, newPullRequest.Issue.GetHTMLURL(), err)
-				return nil
+				fmt.Printf("Failed to fetch bug %s for %s: %s\n", id, issue.GetHTMLURL(), err)
 			}
-			return bz
+			return bug, err
+		},
+		RelatedBug: func(id string) (*tracker.Issue, error) {
+			return l.GetBug(trackerName, id)
+		},
+		RelatedPull: func(prURL string) (*github.PullRequest, error) {
+			return l.GetPullRequestDetails(ctx, prURL)
+		},
+		Files: func() ([]*github.CommitFile, error) {
+			return l.listFiles(ctx, issue.GetHTMLURL())
+		},
+	}), nil
+}
+
+// GetPullRequestDetails returns the pull request (including merge status and labels) by its URL. Every pull request is
+// fetched only once.
+func (l *PullRequestLister) GetPullRequestDetails(ctx context.Context, prURL string) (*github.PullRequest, error) {
+	owner, repo, number, err := parsePullRequestMeta(prURL)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s/%s/%d", owner, repo, number)
+	l.pullCacheLock.Lock()
+	entry, ok := l.pullCache[key]
+	if !ok {
+		entry = &pullCacheEntry{}
+		l.pullCache[key] = entry
+	}
+	l.pullCacheLock.Unlock()
+
+	entry.once.Do(func() {
+		if l.snapshot.Replaying() {
+			entry.pull = &github.PullRequest{}
+			entry.err = l.snapshot.Load(snapshot.PullKind, key, entry.pull)
+			return
+		}
+		entry.pull, _, entry.err = l.ghClient.PullRequests.Get(ctx, owner, repo, number)
+		if entry.err == nil && l.snapshot.Recording() {
+			entry.err = l.snapshot.Save(snapshot.PullKind, key, entry.pull)
 		}
+	})
+	return entry.pull, entry.err
+}
 
-		pullRequests = append(pullRequests, newPullRequest)
+// listFiles returns all files changed by the pull request.
+func (l *PullRequestLister) listFiles(ctx context.Context, prURL string) ([]*github.CommitFile, error) {
+	owner, repo, number, err := parsePullRequestMeta(prURL)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s/%s/%d", owner, repo, number)
+	files := []*github.CommitFile{}
+	if l.snapshot.Replaying() {
+		return files, l.snapshot.Load(snapshot.FilesKind, key, &files)
+	}
+	options := &github.ListOptions{PerPage: 100}
+	for {
+		page, response, err := l.ghClient.PullRequests.ListFiles(ctx, owner, repo, number, options)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, page...)
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	if l.snapshot.Recording() {
+		if err := l.snapshot.Save(snapshot.FilesKind, key, files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// GetBug returns the bug from given tracker. Every bug is fetched only once.
+func (l *PullRequestLister) GetBug(trackerName, id string) (*tracker.Issue, error) {
+	entry := l.bugCacheEntry(trackerName, id)
+	entry.once.Do(func() {
+		entry.bug, entry.err = l.getBug(trackerName, id)
+	})
+	return entry.bug, entry.err
+}
+
+func (l *PullRequestLister) bugCacheEntry(trackerName, id string) *bugCacheEntry {
+	key := trackerName + "/" + id
+	l.bugCacheLock.Lock()
+	defer l.bugCacheLock.Unlock()
+	entry, ok := l.bugCache[key]
+	if !ok {
+		entry = &bugCacheEntry{}
+		l.bugCache[key] = entry
 	}
+	return entry
+}
 
-	return pullRequests, nil
+// getBug fetch the bug from the tracker, retrying with backoff on errors other than the bug not being accessible.
+func (l *PullRequestLister) getBug(trackerName, id string) (*tracker.Issue, error) {
+	var (
+		bug     *tracker.Issue
+		lastErr error
+	)
+	err := wait.ExponentialBackoff(bugFetchBackoff, func() (bool, error) {
+		bug, lastErr = l.trackers.GetIssue(trackerName, id)
+		if lastErr == nil {
+			return true, nil
+		}
+		if tracker.IsNotAccessible(lastErr) {
+			return false, lastErr
+		}
+		klog.V(2).Infof("Retrying to fetch bug %s from %s: %v", id, trackerName, lastErr)
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil, lastErr
+	}
+	return bug, err
 }
 
-func (l *PullRequestLister) ListApprovedForRelease(ctx context.Context, release string) ([]*PullRequest, error) {
+// PrefetchBugs fetch bugs referenced by given pull requests in batches instead of fetching them one by one when they are
+// needed. Pull requests referencing the same bug share the fetched bug and GetBug serves prefetched bugs without fetching
+// them again. Bugs that fail to prefetch are fetched lazily.
+func (l *PullRequestLister) PrefetchBugs(pullRequests []*PullRequest) {
+	bugIDs := map[string]map[string][]*PullRequest{}
+	for _, p := range pullRequests {
+		if p.bug != nil {
+			continue
+		}
+		if _, ok := bugIDs[p.bugTracker]; !ok {
+			bugIDs[p.bugTracker] = map[string][]*PullRequest{}
+		}
+		bugIDs[p.bugTracker][p.bugID] = append(bugIDs[p.bugTracker][p.bugID], p)
+	}
+
+	for trackerName, pulls := range bugIDs {
+		batchTracker, ok := l.trackers[trackerName].(tracker.BatchTracker)
+		if !ok {
+			continue
+		}
+		ids := make([]string, 0, len(pulls))
+		for id := range pulls {
+			ids = append(ids, id)
+		}
+		bugs, err := batchTracker.GetIssues(ids)
+		if err != nil {
+			klog.Warningf("WARNING: Failed to prefetch %d bugs from %s: %v", len(ids), trackerName, err)
+			continue
+		}
+		for id, bug := range bugs {
+			for _, p := range pulls[id] {
+				p.bug = bug
+			}
+			entry := l.bugCacheEntry(trackerName, id)
+			entry.once.Do(func() {
+				entry.bug = bug
+			})
+		}
+		klog.V(2).Infof("Prefetched %d of %d bugs from %s", len(bugs), len(ids), trackerName)
+	}
+}
+
+func (l *PullRequestLister) ListApprovedForRelease(ctx context.Context, release string) ([]*PullRequest, SearchStats, error) {
 	return l.ListForRelease(ctx, release, "label:cherry-pick-approved")
 }
 
-func (l *PullRequestLister) ListCandidatesForRelease(ctx context.Context, release string) ([]*PullRequest, error) {
+func (l *PullRequestLister) ListCandidatesForRelease(ctx context.Context, release string) ([]*PullRequest, SearchStats, error) {
 	return l.ListForRelease(ctx, release, "-label:cherry-pick-approved")
 }
 
-// parseBugNumber takes pull request title "Bug ####: Description" and return the ####
-func parseBugNumber(pullRequestTitle string) string {
-	re := regexp.MustCompile(`bug (\d+):`)
-	matches := re.FindAllString(strings.ToLower(pullRequestTitle), 1)
-	if len(matches) == 0 {
-		return ""
+var (
+	bugzillaTitleRe = regexp.MustCompile(`(?i)bug (\d+):`)
+	jiraTitleRe     = regexp.MustCompile(`\b([A-Z][A-Z0-9]+-\d+):`)
+)
+
+// parseBugReference takes pull request title "Bug ####: Description" or "OCPBUGS-####: Description" and return the tracker name
+// and the #### (or OCPBUGS-####).
+func parseBugReference(pullRequestTitle string) (string, string) {
+	if matches := bugzillaTitleRe.FindStringSubmatch(pullRequestTitle); len(matches) > 1 {
+		return tracker.Bugzilla, matches[1]
 	}
-	return strings.TrimSpace(strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(matches[0], ":")), "bug"))
+	if matches := jiraTitleRe.FindStringSubmatch(pullRequestTitle); len(matches) > 1 {
+		return tracker.Jira, matches[1]
+	}
+	return "", ""
 }
 
-func buildGithubSearchQuery(query, release string) string {
-	return fmt.Sprintf(query, release)
+func buildGithubSearchQuery(search config.SearchConfig, release string) string {
+	query := []string{}
+	for _, org := range search.Orgs {
+		query = append(query, "org:"+org)
+	}
+	for _, repo := range search.IncludeRepos {
+		query = append(query, "repo:"+repo)
+	}
+	for _, label := range search.RequireLabels {
+		query = append(query, "label:"+label)
+	}
+	for _, branch := range search.Branches {
+		query = append(query, "base:"+strings.ReplaceAll(branch, "{release}", release))
+	}
+	query = append(query, "is:open")
+	if search.ExcludeRepos != nil {
+		for _, repo := range *search.ExcludeRepos {
+			query = append(query, "-repo:"+repo)
+		}
+	}
+	return strings.Join(query, " ")
 }
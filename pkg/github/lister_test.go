@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestParseBugReference(t *testing.T) {
+	tests := []struct {
+		title   string
+		tracker string
+		id      string
+	}{
+		{title: "Bug 1234567: fix the operator status", tracker: tracker.Bugzilla, id: "1234567"},
+		{title: "[release-4.7] bug 1234567: fix the operator status", tracker: tracker.Bugzilla, id: "1234567"},
+		{title: "OCPBUGS-123: fix the operator status", tracker: tracker.Jira, id: "OCPBUGS-123"},
+		{title: "[release-4.12] OCPBUGS-123: fix the operator status", tracker: tracker.Jira, id: "OCPBUGS-123"},
+		{title: "Bump dependencies"},
+		{title: "OCPBUGS-123 without colon"},
+	}
+	for _, test := range tests {
+		trackerName, id := parseBugReference(test.title)
+		if trackerName != test.tracker || id != test.id {
+			t.Errorf("%q: expected %q %q, got %q %q", test.title, test.tracker, test.id, trackerName, id)
+		}
+	}
+}
+
+// searchFake is the fake Bugzilla client that search only the requested bugs (the vendored fake returns all bugs).
+// Bugs in batchMisses are left out of searches for more than one bug, like when they fail in the batch.
+type searchFake struct {
+	*bugzilla.Fake
+	batchMisses map[int]bool
+	searches    int
+}
+
+func (f *searchFake) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	f.searches++
+	bugs := []*bugzilla.Bug{}
+	for _, id := range query.BugIDs {
+		bugID, _ := strconv.Atoi(id)
+		if bug, ok := f.Bugs[bugID]; ok && (len(query.BugIDs) == 1 || !f.batchMisses[bugID]) {
+			bugs = append(bugs, &bug)
+		}
+	}
+	return bugs, nil
+}
+
+func TestPrefetchBugs(t *testing.T) {
+	client := &searchFake{
+		Fake: &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+			1: {ID: 1, Summary: "shared bug"},
+			2: {ID: 2, Summary: "missing in batch"},
+		}},
+		batchMisses: map[int]bool{2: true},
+	}
+	lister := &PullRequestLister{
+		trackers: tracker.NewTrackers(tracker.NewBugzillaTrackerWithClient(client)),
+		bugCache: map[string]*bugCacheEntry{},
+	}
+	pulls := []*PullRequest{}
+	for i, bugID := range []int{1, 1, 1, 2, 3, 3} {
+		p, err := lister.newPullRequest(context.TODO(), &github.Issue{
+			Title:   github.String(fmt.Sprintf("Bug %d: fix", bugID)),
+			HTMLURL: github.String(fmt.Sprintf("https://github.com/openshift/origin/pull/%d", i)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pulls = append(pulls, p)
+	}
+
+	lister.PrefetchBugs(pulls)
+	if client.searches != 1 {
+		t.Errorf("expected single search, got %d", client.searches)
+	}
+	if pulls[0].bug == nil || pulls[0].bug != pulls[1].bug || pulls[0].bug != pulls[2].bug {
+		t.Errorf("expected pull requests referencing bug 1 to share the same bug, got %p %p %p", pulls[0].bug, pulls[1].bug, pulls[2].bug)
+	}
+	if pulls[3].bug != nil || pulls[4].bug != nil || pulls[5].bug != nil {
+		t.Errorf("expected bugs 2 and 3 not to be prefetched")
+	}
+	// prefetched bugs are cached for related bug lookups (eg. backport chain)
+	if bug, err := lister.GetBug(tracker.Bugzilla, "1"); err != nil || bug != pulls[0].bug {
+		t.Errorf("expected cached bug 1, got %+v (%v)", bug, err)
+	}
+	if client.searches != 1 {
+		t.Errorf("expected prefetched bug not to be fetched again, got %d searches", client.searches)
+	}
+
+	// bugs missing in the batch are fetched lazily
+	if bug := pulls[3].Bug(); bug == nil || bug.Summary != "missing in batch" {
+		t.Errorf("expected bug 2 to be fetched lazily, got %+v (%v)", bug, pulls[3].BugError())
+	}
+	if bug := pulls[4].Bug(); bug != nil || !tracker.IsNotAccessible(pulls[4].BugError()) {
+		t.Errorf("expected bug 3 to be not accessible, got %+v (%v)", bug, pulls[4].BugError())
+	}
+	// the failed fetch is shared by the pull requests referencing the same bug
+	if bug := pulls[5].Bug(); bug != nil || pulls[5].BugError() != pulls[4].BugError() {
+		t.Errorf("expected bug 3 to be fetched once, got %+v (%v)", bug, pulls[5].BugError())
+	}
+	if pulls[0].Bug().Summary != "shared bug" {
+		t.Errorf("unexpected bug 1: %+v", pulls[0].Bug())
+	}
+	if client.searches != 3 {
+		t.Errorf("expected 2 lazy searches, got %d", client.searches)
+	}
+}
+
+func TestSearchAllDeduplicatesSplitSearches(t *testing.T) {
+	store, err := snapshot.NewRecorder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := config.SearchConfig{Orgs: []string{"openshift"}, IncludeRepos: []string{"openshift/origin"}, Branches: []string{"release-{release}"}}
+	issue := func(url string) *github.Issue {
+		return &github.Issue{HTMLURL: github.String(url)}
+	}
+	pages := []searchResultPage{
+		{Query: buildGithubSearchQuery(search, "4.7") + " is:pr", Total: 1500},
+		{
+			Query:  buildGithubSearchQuery(config.SearchConfig{Orgs: []string{"openshift"}, Branches: search.Branches}, "4.7") + " is:pr",
+			Total:  2,
+			Issues: []*github.Issue{issue("https://github.com/openshift/origin/pull/1"), issue("https://github.com/openshift/api/pull/2")},
+		},
+		{
+			Query:  buildGithubSearchQuery(config.SearchConfig{IncludeRepos: []string{"openshift/origin"}, Branches: search.Branches}, "4.7") + " is:pr",
+			Total:  1,
+			Issues: []*github.Issue{issue("https://github.com/openshift/origin/pull/1")},
+		},
+	}
+	for _, page := range pages {
+		if err := store.Save(snapshot.SearchKind, fmt.Sprintf("%s page:%d", page.Query, page.Page), page); err != nil {
+			t.Fatal(err)
+		}
+	}
+	replay, err := snapshot.NewReplayer(store.Path(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lister := &PullRequestLister{snapshot: replay}
+	issues, stats, err := lister.searchAll(context.TODO(), search, "4.7", "is:pr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("expected 2 unique issues, got %d", len(issues))
+	}
+	if stats.Total != 1500 || stats.Truncated {
+		t.Errorf("expected 1500 results not truncated, got %+v", stats)
+	}
+
+	// search that can't be split any further is truncated
+	single := config.SearchConfig{Orgs: []string{"openshift"}, Branches: search.Branches}
+	if err := store.Save(snapshot.SearchKind, buildGithubSearchQuery(single, "4.7")+" label:cherry-pick-approved page:0", searchResultPage{Total: 1200, Issues: pages[1].Issues}); err != nil {
+		t.Fatal(err)
+	}
+	_, stats, err = lister.searchAll(context.TODO(), single, "4.7", "label:cherry-pick-approved")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Total != 1200 || !stats.Truncated {
+		t.Errorf("expected 1200 results truncated, got %+v", stats)
+	}
+}
@@ -1,23 +1,134 @@
 package github
 
 import (
-	"github.com/eparis/bugzilla"
+	"sync"
+
 	"github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/backport"
+	"github.com/openshift/patchmanager/pkg/tracker"
 )
 
 type PullRequest struct {
 	Issue *github.Issue
 	Score float32
+	// ScoreBreakdown holds the score contributed by each classifier
+	ScoreBreakdown map[string]float32
 
 	// do lazy fetch for bugs when needed to speed up sorting
-	getBugFn func(int) *bugzilla.Bug
-	bugID    int
-	bug      *bugzilla.Bug
+	getBugFn   func(string) (*tracker.Issue, error)
+	bugTracker string
+	bugID      string
+	bug        *tracker.Issue
+	bugErr     error
+
+	// fetch of bugs related to the pull request bug is cached as multiple pull requests often share them
+	getRelatedBugFn  func(string) (*tracker.Issue, error)
+	getRelatedPullFn func(string) (*github.PullRequest, error)
+
+	// details and changed files are fetched only by classifiers that need them
+	getFilesFn func() ([]*github.CommitFile, error)
+	filesOnce  sync.Once
+	files      []*github.CommitFile
+	filesErr   error
 }
 
-func (p *PullRequest) Bug() *bugzilla.Bug {
-	if p.bug == nil {
-		p.bug = p.getBugFn(p.bugID)
+// Fetchers fetch the pull request bug, related bugs and pull requests and changed files when they are needed.
+type Fetchers struct {
+	Bug         func(id string) (*tracker.Issue, error)
+	RelatedBug  func(id string) (*tracker.Issue, error)
+	RelatedPull func(prURL string) (*github.PullRequest, error)
+	Files       func() ([]*github.CommitFile, error)
+}
+
+// NewPullRequest returns pull request referencing given bug, the bug and everything else is fetched lazily by fetchers.
+func NewPullRequest(issue *github.Issue, bugTracker, bugID string, fetchers Fetchers) *PullRequest {
+	return &PullRequest{
+		Issue:            issue,
+		bugTracker:       bugTracker,
+		bugID:            bugID,
+		getBugFn:         fetchers.Bug,
+		getRelatedBugFn:  fetchers.RelatedBug,
+		getRelatedPullFn: fetchers.RelatedPull,
+		getFilesFn:       fetchers.Files,
+	}
+}
+
+// Bug returns the bug referenced in the pull request title. When the bug can't be fetched, nil is returned and BugError()
+// returns the reason.
+func (p *PullRequest) Bug() *tracker.Issue {
+	if p.bug == nil && p.bugErr == nil {
+		p.bug, p.bugErr = p.getBugFn(p.bugID)
 	}
 	return p.bug
 }
+
+// BugError returns the error that occurred when fetching the bug.
+func (p *PullRequest) BugError() error {
+	p.Bug()
+	return p.bugErr
+}
+
+// RelatedBug fetch another bug from the same tracker as the pull request bug (eg. the bug it was cloned from).
+func (p *PullRequest) RelatedBug(id string) (*tracker.Issue, error) {
+	return p.getRelatedBugFn(id)
+}
+
+// RelatedPullRequest fetch another pull request by its URL (eg. pull request linked to a bug).
+func (p *PullRequest) RelatedPullRequest(prURL string) (*github.PullRequest, error) {
+	return p.getRelatedPullFn(prURL)
+}
+
+// Details returns the pull request details (eg. number of additions, deletions and changed files).
+func (p *PullRequest) Details() (*github.PullRequest, error) {
+	return p.getRelatedPullFn(p.Issue.GetHTMLURL())
+}
+
+// Files returns the files changed by the pull request.
+func (p *PullRequest) Files() ([]*github.CommitFile, error) {
+	p.filesOnce.Do(func() {
+		p.files, p.filesErr = p.getFilesFn()
+	})
+	return p.files, p.filesErr
+}
+
+// BugID returns the bug number (or Jira issue key) referenced in the pull request title.
+func (p *PullRequest) BugID() string {
+	return p.bugID
+}
+
+// UnverifiedParentBug returns the first parent bug (the bug this bug was cloned from, targeting a newer release) that is not
+// in one of the verified statuses. Dependencies that can't be fetched are reported (the ID together with the error) only
+// when no other dependency is the parent, as they might be unrelated (eg. private) bugs.
+// Empty ID means all parent bugs are verified or the bug has no parent.
+func (p *PullRequest) UnverifiedParentBug(verifiedStatuses []string) (string, *tracker.Issue, error) {
+	if len(verifiedStatuses) == 0 {
+		verifiedStatuses = tracker.DefaultVerifiedStatuses
+	}
+	var (
+		failedID  string
+		failedErr error
+		hasParent bool
+	)
+	for _, id := range p.Bug().DependsOn {
+		parent, err := p.RelatedBug(id)
+		if err != nil {
+			if failedErr == nil {
+				failedID, failedErr = id, err
+			}
+			continue
+		}
+		// same release tracker bugs and other dependencies are not the clone parent
+		if !backport.IsParent(p.Bug(), parent) {
+			continue
+		}
+		hasParent = true
+		if !tracker.HasStatus(parent, verifiedStatuses) {
+			return id, parent, nil
+		}
+	}
+	if !hasParent && failedErr != nil {
+		return failedID, nil, failedErr
+	}
+	return "", nil, nil
+}
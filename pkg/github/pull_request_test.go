@@ -0,0 +1,47 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestUnverifiedParentBug(t *testing.T) {
+	bugs := map[string]*tracker.Issue{
+		// same release tracker bug and unrelated older dependency are not parents
+		"2": {ID: "2", TargetRelease: []string{"4.7.z"}, Status: "NEW"},
+		"3": {ID: "3", TargetRelease: []string{"4.6.z"}, Status: "NEW"},
+		"4": {ID: "4", TargetRelease: []string{"4.8.0"}, Status: "VERIFIED"},
+		"5": {ID: "5", TargetRelease: []string{"4.8.0"}, Status: "ON_QA"},
+	}
+	tests := []struct {
+		dependsOn  []string
+		expectedID string
+		expectErr  bool
+	}{
+		{dependsOn: []string{"2", "3", "4"}},
+		{dependsOn: []string{"2", "5"}, expectedID: "5"},
+		// dependencies that can't be fetched are reported only when no other dependency is the parent
+		{dependsOn: []string{"6"}, expectedID: "6", expectErr: true},
+		{dependsOn: []string{"2", "6"}, expectedID: "6", expectErr: true},
+		{dependsOn: []string{"6", "4"}},
+		{dependsOn: []string{"6", "5"}, expectedID: "5"},
+		{},
+	}
+	for _, test := range tests {
+		p := &PullRequest{
+			bug: &tracker.Issue{ID: "1", TargetRelease: []string{"4.7.z"}, DependsOn: test.dependsOn},
+			getRelatedBugFn: func(id string) (*tracker.Issue, error) {
+				if bug, ok := bugs[id]; ok {
+					return bug, nil
+				}
+				return nil, fmt.Errorf("bug %s not found", id)
+			},
+		}
+		id, _, err := p.UnverifiedParentBug(nil)
+		if id != test.expectedID || (err != nil) != test.expectErr {
+			t.Errorf("depends on %v: expected %q (error: %v), got %q (%v)", test.dependsOn, test.expectedID, test.expectErr, id, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+)
+
+const (
+	// searchResultsLimit is the maximum number of results Github search API return for a single query.
+	searchResultsLimit = 1000
+	// searchPageSize is the maximum page size supported by Github search API.
+	searchPageSize = 100
+)
+
+// SearchStats describe the Github search the pull requests were listed by.
+type SearchStats struct {
+	// Total is the number of results reported by Github.
+	Total int
+	// Truncated is true when some results were not returned because of the search API limit.
+	Truncated bool
+}
+
+// searchAll return all issues matching the search configuration. When the query match more issues than Github search API is
+// able to return, the query is split by organizations/repositories first and then by branches.
+func (l *PullRequestLister) searchAll(ctx context.Context, search config.SearchConfig, release, labels string) ([]*github.Issue, SearchStats, error) {
+	query := buildGithubSearchQuery(search, release) + " " + labels
+	splits := splitSearch(search)
+	issues, total, err := l.searchIssues(ctx, query, len(splits) == 0)
+	if err != nil {
+		return nil, SearchStats{}, err
+	}
+	stats := SearchStats{Total: total}
+	if total <= searchResultsLimit {
+		return issues, stats, nil
+	}
+	if len(splits) == 0 {
+		klog.Warningf("WARNING: Search query %q matched %d results, only first %d are used", query, total, searchResultsLimit)
+		stats.Truncated = true
+		return issues, stats, nil
+	}
+
+	klog.V(2).Infof("Search query %q matched %d results, splitting it into %d queries", query, total, len(splits))
+	// split searches overlap when a repository from IncludeRepos belongs to one of the organizations
+	seen := map[string]bool{}
+	for i := range splits {
+		result, splitStats, err := l.searchAll(ctx, splits[i], release, labels)
+		if err != nil {
+			return nil, SearchStats{}, err
+		}
+		stats.Truncated = stats.Truncated || splitStats.Truncated
+		for _, issue := range result {
+			if seen[issue.GetHTMLURL()] {
+				continue
+			}
+			seen[issue.GetHTMLURL()] = true
+			issues = append(issues, issue)
+		}
+	}
+	return issues, stats, nil
+}
+
+// searchIssues pages through all results of given query and return the issues together with the total count reported by
+// Github. If the total count exceeds the search API limit, no issues are returned unless truncate is set.
+func (l *PullRequestLister) searchIssues(ctx context.Context, query string, truncate bool) ([]*github.Issue, int, error) {
+	options := &github.SearchOptions{Sort: "updated", ListOptions: github.ListOptions{PerPage: searchPageSize}}
+	var issues []*github.Issue
+	for {
+		page, err := l.searchPage(ctx, query, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		if page.Total > searchResultsLimit && !truncate {
+			return nil, page.Total, nil
+		}
+		issues = append(issues, page.Issues...)
+		// Github refuse to return pages past the search limit
+		if page.NextPage == 0 || len(issues) >= searchResultsLimit {
+			return issues, page.Total, nil
+		}
+		options.Page = page.NextPage
+	}
+}
+
+// searchResultPage is a single page of search results as recorded in the snapshot.
+type searchResultPage struct {
+	Query    string          `json:"query"`
+	Page     int             `json:"page"`
+	Total    int             `json:"total"`
+	NextPage int             `json:"nextPage"`
+	Issues   []*github.Issue `json:"issues"`
+}
+
+func (l *PullRequestLister) searchPage(ctx context.Context, query string, options *github.SearchOptions) (*searchResultPage, error) {
+	key := fmt.Sprintf("%s page:%d", query, options.Page)
+	page := &searchResultPage{}
+	if l.snapshot.Replaying() {
+		return page, l.snapshot.Load(snapshot.SearchKind, key, page)
+	}
+	result, resp, err := l.ghClient.Search.Issues(ctx, query, options)
+	if err != nil {
+		return nil, err
+	}
+	page = &searchResultPage{
+		Query:    query,
+		Page:     options.Page,
+		Total:    result.GetTotal(),
+		NextPage: resp.NextPage,
+		Issues:   result.Issues,
+	}
+	if l.snapshot.Recording() {
+		if err := l.snapshot.Save(snapshot.SearchKind, key, page); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// splitSearch split the search into one search per organization or repository. Searches for a single organization are
+// split by branches. Nil is returned if the search can't be split any further.
+func splitSearch(search config.SearchConfig) []config.SearchConfig {
+	var result []config.SearchConfig
+	if len(search.Orgs)+len(search.IncludeRepos) > 1 {
+		for _, org := range search.Orgs {
+			s := search
+			s.Orgs, s.IncludeRepos = []string{org}, nil
+			result = append(result, s)
+		}
+		for _, repo := range search.IncludeRepos {
+			s := search
+			s.Orgs, s.IncludeRepos = nil, []string{repo}
+			result = append(result, s)
+		}
+		return result
+	}
+	if len(search.Branches) > 1 {
+		for _, branch := range search.Branches {
+			s := search
+			s.Branches = []string{branch}
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/cmd/util"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/snapshot"
+)
+
+func TestRecordReplayExplicitlyEmptyExcludeRepos(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "patchmanager.yaml")
+	if err := ioutil.WriteFile(configFile, []byte("search:\n  orgs: [openshift]\n  excludeRepos: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recordDir := filepath.Join(dir, "snapshot")
+
+	// record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query().Get("q"); strings.Contains(query, "-repo:") {
+			t.Errorf("expected no repository to be excluded, got query %q", query)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count":1,"items":[{"number":1,"title":"Bug 1: fix","html_url":"https://github.com/openshift/origin/pull/1","pull_request":{}}]}`))
+	}))
+	recorder, err := snapshot.NewRecorder(recordDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.GetConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.RecordConfig(recorder, c, "4.7"); err != nil {
+		t.Fatal(err)
+	}
+	lister := NewPullRequestLister(context.TODO(), "", "", "", c.SearchConfig).WithSnapshot(recorder)
+	lister.ghClient.BaseURL, _ = url.Parse(server.URL + "/")
+	recorded, _, err := lister.ListCandidatesForRelease(context.TODO(), "4.7")
+	server.Close()
+	if err != nil || len(recorded) != 1 {
+		t.Fatalf("expected 1 recorded pull request, got %d (%v)", len(recorded), err)
+	}
+
+	// replay with the recorded config
+	replayer, err := snapshot.NewReplayer(recordDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = config.GetConfig(replayer.Path(snapshot.ConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, _, err := NewPullRequestLister(context.TODO(), "", "", "", c.SearchConfig).WithSnapshot(replayer).ListCandidatesForRelease(context.TODO(), c.Release)
+	if err != nil || len(replayed) != 1 {
+		t.Fatalf("expected 1 replayed pull request, got %d (%v)", len(replayed), err)
+	}
+	if !replayer.Now().Equal(recorder.Now()) {
+		t.Errorf("expected replay at the recorded time %s, got %s", recorder.Now(), replayer.Now())
+	}
+}
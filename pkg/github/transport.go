@@ -0,0 +1,160 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// rateLimitMaxRetries is how many times a rate limited request is retried before the error is returned to the caller.
+	rateLimitMaxRetries = 5
+	// secondaryRateLimitBackoff is the initial wait time for secondary (abuse) rate limits without Retry-After header.
+	secondaryRateLimitBackoff = 10 * time.Second
+)
+
+// newGithubClient returns Github client authenticated with the token that waits for rate limit reset and retry requests
+// that hit secondary rate limits.
+func newGithubClient(ctx context.Context, token string) *github.Client {
+	oauthClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	oauthClient.Transport = &rateLimitTransport{next: oauthClient.Transport}
+	return github.NewClient(oauthClient)
+}
+
+// rateLimitTransport is a http.RoundTripper that handle Github primary and secondary rate limits.
+type rateLimitTransport struct {
+	next http.RoundTripper
+	// sleep waits for the duration or until the context is done, time.After based when not set (tests replace it)
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := retryRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil {
+			return resp, err
+		}
+		logRemainingQuota(resp)
+
+		wait, limited := rateLimitWait(resp, attempt)
+		if !limited {
+			// go-github refuses every request after a response with exhausted quota until the reset, without sending
+			// it, so wait for the reset here instead of failing the next request
+			if wait, exhausted := primaryRateLimitWait(resp); exhausted {
+				klog.Warningf("WARNING: Github rate limit exhausted by %s %s, waiting %s for reset", req.Method, req.URL.Path, wait.Round(time.Second))
+				if err := t.wait(req.Context(), wait); err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+			}
+			return resp, nil
+		}
+		// request body was consumed by the previous attempt and can't be sent again
+		if attempt >= rateLimitMaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		klog.Warningf("WARNING: Github rate limit hit for %s %s, retrying in %s (attempt %d/%d)", req.Method, req.URL.Path, wait.Round(time.Second), attempt+1, rateLimitMaxRetries)
+		if err := t.wait(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *rateLimitTransport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// retryRequest returns the request for the attempt, retries get a clone with fresh body as the RoundTripper must not
+// modify the original request.
+func retryRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 {
+		return req, nil
+	}
+	clone := req.Clone(req.Context())
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// rateLimitWait returns how long to wait before retrying the request and whether the response is rate limited at all.
+func rateLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	// primary rate limit: wait for the quota to reset
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return primaryRateLimitWait(resp)
+	}
+
+	// secondary rate limit: Github tell us how long to wait or we back off exponentially
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(retryAfter) * time.Second, true
+	}
+	if isSecondaryRateLimit(resp) {
+		return secondaryRateLimitBackoff * time.Duration(1<<uint(attempt)), true
+	}
+	return 0, false
+}
+
+// primaryRateLimitWait returns how long to wait for the quota reset when the response says no requests remain.
+func primaryRateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(time.Unix(reset, 0)) + time.Second
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait, true
+}
+
+// isSecondaryRateLimit check the response body for secondary (abuse) rate limit message and restore the body for the caller.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+}
+
+func logRemainingQuota(resp *http.Response) {
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); len(remaining) > 0 {
+		klog.V(2).Infof("Github rate limit: %s of %s requests remaining (%s %s)", remaining, resp.Header.Get("X-RateLimit-Limit"),
+			resp.Request.Method, resp.Request.URL.Path)
+	}
+}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeResponse is a response the test server sends for one request.
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	reset := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).Unix(), 10)
+	}
+	ok := fakeResponse{status: http.StatusOK, body: "{}"}
+	tests := []struct {
+		name      string
+		responses []fakeResponse
+		status    int
+		requests  int
+		waits     []time.Duration
+	}{
+		{
+			name:      "not rate limited",
+			responses: []fakeResponse{ok},
+			status:    http.StatusOK,
+			requests:  1,
+		},
+		{
+			name:      "forbidden without rate limit",
+			responses: []fakeResponse{{status: http.StatusForbidden, body: "Resource not accessible by integration"}},
+			status:    http.StatusForbidden,
+			requests:  1,
+		},
+		{
+			name: "primary rate limit waits for the reset",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(time.Minute)}},
+				ok,
+			},
+			status:   http.StatusOK,
+			requests: 2,
+			waits:    []time.Duration{time.Minute},
+		},
+		{
+			name: "exhausted quota of successful response waits for the reset",
+			responses: []fakeResponse{
+				{status: http.StatusOK, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(30 * time.Second)}, body: "{}"},
+			},
+			status:   http.StatusOK,
+			requests: 1,
+			waits:    []time.Duration{30 * time.Second},
+		},
+		{
+			name: "secondary rate limit with Retry-After",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "3"}},
+				ok,
+			},
+			status:   http.StatusOK,
+			requests: 2,
+			waits:    []time.Duration{3 * time.Second},
+		},
+		{
+			name: "secondary rate limit message backs off exponentially",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+				{status: http.StatusForbidden, body: `{"message": "You have triggered an abuse detection mechanism."}`},
+				ok,
+			},
+			status:   http.StatusOK,
+			requests: 3,
+			waits:    []time.Duration{secondaryRateLimitBackoff, 2 * secondaryRateLimitBackoff},
+		},
+		{
+			name: "give up after maximum retries",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"Retry-After": "1"}},
+			},
+			status:   http.StatusForbidden,
+			requests: 6,
+			waits:    []time.Duration{time.Second, time.Second, time.Second, time.Second, time.Second},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bodies := []string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				response := test.responses[len(test.responses)-1]
+				if len(bodies) <= len(test.responses) {
+					response = test.responses[len(bodies)-1]
+				}
+				for k, v := range response.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(response.status)
+				w.Write([]byte(response.body))
+			}))
+			defer server.Close()
+
+			waits := []time.Duration{}
+			transport := &rateLimitTransport{
+				next: http.DefaultTransport,
+				sleep: func(ctx context.Context, d time.Duration) error {
+					waits = append(waits, d)
+					return nil
+				},
+			}
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/repos/org/repo/issues/1/labels", strings.NewReader(`["cherry-pick-approved"]`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Errorf("expected status %d, got %d", test.status, resp.StatusCode)
+			}
+			if len(bodies) != test.requests {
+				t.Errorf("expected %d requests, got %d", test.requests, len(bodies))
+			}
+			for i, body := range bodies {
+				if body != `["cherry-pick-approved"]` {
+					t.Errorf("expected request %d to send the original body, got %q", i+1, body)
+				}
+			}
+			if len(waits) != len(test.waits) {
+				t.Fatalf("expected waits %v, got %v", test.waits, waits)
+			}
+			for i := range waits {
+				// reset time has one second precision and one second is added to be sure the quota is reset
+				if waits[i] < test.waits[i]-time.Second || waits[i] > test.waits[i]+2*time.Second {
+					t.Errorf("expected wait %d to be about %s, got %s", i+1, test.waits[i], waits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the request itself fails on the cancelled context before any wait
+	if _, err := (&rateLimitTransport{next: http.DefaultTransport}).RoundTrip(req); err == nil {
+		t.Errorf("expected error for cancelled request")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	transport := &rateLimitTransport{
+		next: http.DefaultTransport,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			return context.Canceled
+		},
+	}
+	if _, err := transport.RoundTrip(req); err != context.Canceled {
+		t.Errorf("expected the wait to be interrupted, got %v", err)
+	}
+}
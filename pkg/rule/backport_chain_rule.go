@@ -0,0 +1,32 @@
+package rule
+
+import (
+	"github.com/openshift/patchmanager/pkg/backport"
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// BackportChainRule refuse pull requests which fix is not merged in all releases newer than the release being triaged,
+// as skipping a release breaks upgrades.
+type BackportChainRule struct {
+	Config *config.BackportChainRuleConfig
+	// Release is the release being triaged (eg. 4.7)
+	Release string
+}
+
+func (b *BackportChainRule) Name() string {
+	return "backportChain"
+}
+
+func (b *BackportChainRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
+	if !b.Config.Required {
+		return nil, true
+	}
+	walker := &backport.Walker{GetBug: pullRequest.RelatedBug, GetPullRequest: pullRequest.RelatedPullRequest}
+	gaps := walker.Walk(pullRequest.Bug()).Gaps(b.Release, b.Config.AcceptApproved)
+	result := []string{}
+	for _, gap := range gaps {
+		result = append(result, "skipping because "+gap)
+	}
+	return result, len(result) == 0
+}
@@ -0,0 +1,103 @@
+package rule
+
+import (
+	"reflect"
+	"testing"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestBackportChainRule(t *testing.T) {
+	merged := &githubapi.PullRequest{Merged: githubapi.Bool(true), State: githubapi.String("closed")}
+	approved := &githubapi.PullRequest{State: githubapi.String("open"), Labels: []*githubapi.Label{{Name: githubapi.String("cherry-pick-approved")}}}
+	open := &githubapi.PullRequest{State: githubapi.String("open")}
+	tests := []struct {
+		name           string
+		dependsOn      []string
+		blocks         []string
+		bugs           map[string]*tracker.Issue
+		pulls          map[string]*githubapi.PullRequest
+		acceptApproved bool
+		reasons        []string
+	}{
+		{
+			name:      "merged chain",
+			dependsOn: []string{"2"},
+			bugs: map[string]*tracker.Issue{
+				"2": {ID: "2", TargetRelease: []string{"4.7.z"}, DependsOn: []string{"3"}, PullRequests: []string{"pr-4.7"}},
+				"3": {ID: "3", TargetRelease: []string{"4.8.0"}, PullRequests: []string{"pr-4.8"}},
+			},
+			pulls: map[string]*githubapi.PullRequest{"pr-4.7": merged, "pr-4.8": merged},
+		},
+		{
+			name:      "merged chain with unrelated private bug",
+			dependsOn: []string{"2"},
+			blocks:    []string{"10"},
+			bugs: map[string]*tracker.Issue{
+				"2": {ID: "2", TargetRelease: []string{"4.7.z"}, DependsOn: []string{"3"}, Blocks: []string{"1"}, PullRequests: []string{"pr-4.7"}},
+				"3": {ID: "3", TargetRelease: []string{"4.8.0"}, PullRequests: []string{"pr-4.8"}},
+			},
+			pulls: map[string]*githubapi.PullRequest{"pr-4.7": merged, "pr-4.8": merged},
+		},
+		{
+			name:      "missing newer release",
+			dependsOn: []string{"3"},
+			bugs: map[string]*tracker.Issue{
+				"3": {ID: "3", TargetRelease: []string{"4.8.0"}, PullRequests: []string{"pr-4.8"}},
+			},
+			pulls:   map[string]*githubapi.PullRequest{"pr-4.8": merged},
+			reasons: []string{"skipping because 4.7 backport missing"},
+		},
+		{
+			name:      "approved newer release",
+			dependsOn: []string{"2"},
+			bugs: map[string]*tracker.Issue{
+				"2": {ID: "2", TargetRelease: []string{"4.7.z"}, PullRequests: []string{"pr-4.7", "pr-4.7-other"}},
+			},
+			pulls:   map[string]*githubapi.PullRequest{"pr-4.7": approved, "pr-4.7-other": open},
+			reasons: []string{"skipping because 4.7 backport not merged (bug 2)"},
+		},
+		{
+			name:      "approved newer release accepted",
+			dependsOn: []string{"2"},
+			bugs: map[string]*tracker.Issue{
+				"2": {ID: "2", TargetRelease: []string{"4.7.z"}, PullRequests: []string{"pr-4.7"}},
+			},
+			pulls:          map[string]*githubapi.PullRequest{"pr-4.7": approved},
+			acceptApproved: true,
+		},
+		{
+			name:      "open newer release is not accepted",
+			dependsOn: []string{"2"},
+			bugs: map[string]*tracker.Issue{
+				"2": {ID: "2", TargetRelease: []string{"4.7.z"}, PullRequests: []string{"pr-4.7"}},
+			},
+			pulls:          map[string]*githubapi.PullRequest{"pr-4.7": open},
+			acceptApproved: true,
+			reasons:        []string{"skipping because 4.7 backport not merged (bug 2)"},
+		},
+		{
+			name:      "parent bug can't be fetched",
+			dependsOn: []string{"2"},
+			reasons:   []string{"skipping because bug 2 in the backport chain can't be fetched: bug 2 not found"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pullRequest := fake.NewPullRequest(fake.PullRequest{
+				Bug:          &tracker.Issue{ID: "1", TargetRelease: []string{"4.6.z"}, DependsOn: test.dependsOn, Blocks: test.blocks},
+				RelatedBugs:  test.bugs,
+				RelatedPulls: test.pulls,
+			})
+			rule := &BackportChainRule{Config: &config.BackportChainRuleConfig{Required: true, AcceptApproved: test.acceptApproved}, Release: "4.6"}
+			reasons, ok := rule.Evaluate(pullRequest)
+			if ok != (len(test.reasons) == 0) || !reflect.DeepEqual(reasons, append([]string{}, test.reasons...)) {
+				t.Errorf("expected %q, got %v %q", test.reasons, ok, reasons)
+			}
+		})
+	}
+}
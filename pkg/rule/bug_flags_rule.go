@@ -0,0 +1,38 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// BugFlagsRule refuse pull requests with bugs that have any of the refused flags or miss any of the required flags.
+type BugFlagsRule struct {
+	Config *config.BugFlagsRuleConfig
+}
+
+func (b *BugFlagsRule) Name() string {
+	return "bugFlags"
+}
+
+func (b *BugFlagsRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
+	result := []string{}
+	flags := map[string]bool{}
+	for _, f := range pullRequest.Bug().Flags {
+		flags[f] = true
+	}
+
+	for _, f := range b.Config.RefuseOnFlag {
+		if flags[f] {
+			result = append(result, fmt.Sprintf("skipping because bug has %q flag", f))
+		}
+	}
+	for _, f := range b.Config.RequireFlag {
+		if !flags[f] {
+			result = append(result, fmt.Sprintf("skipping because bug does not have %q flag", f))
+		}
+	}
+
+	return result, len(result) == 0
+}
@@ -0,0 +1,62 @@
+package rule
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestBugFlagsRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.BugFlagsRuleConfig
+		flags   []string
+		reasons []string
+	}{
+		{
+			name:  "not configured",
+			flags: []string{"blocker-"},
+		},
+		{
+			name:    "refused flag",
+			config:  config.BugFlagsRuleConfig{RefuseOnFlag: []string{"blocker-"}},
+			flags:   []string{"blocker-", "backport+"},
+			reasons: []string{`skipping because bug has "blocker-" flag`},
+		},
+		{
+			name:   "refused flag with other status",
+			config: config.BugFlagsRuleConfig{RefuseOnFlag: []string{"blocker-"}},
+			flags:  []string{"blocker+", "blocker?"},
+		},
+		{
+			name:   "required flag",
+			config: config.BugFlagsRuleConfig{RequireFlag: []string{"backport+"}},
+			flags:  []string{"backport+"},
+		},
+		{
+			name:    "required flag with other status",
+			config:  config.BugFlagsRuleConfig{RequireFlag: []string{"backport+"}},
+			flags:   []string{"backport?"},
+			reasons: []string{`skipping because bug does not have "backport+" flag`},
+		},
+		{
+			name:    "refused and required flags",
+			config:  config.BugFlagsRuleConfig{RefuseOnFlag: []string{"blocker-"}, RequireFlag: []string{"backport+", "requires_doc_text+"}},
+			flags:   []string{"blocker-", "backport+"},
+			reasons: []string{`skipping because bug has "blocker-" flag`, `skipping because bug does not have "requires_doc_text+" flag`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := &BugFlagsRule{Config: &test.config}
+			pullRequest := fake.NewPullRequest(fake.PullRequest{Bug: &tracker.Issue{ID: "1", Flags: test.flags}})
+			reasons, ok := rule.Evaluate(pullRequest)
+			if ok != (len(test.reasons) == 0) || !reflect.DeepEqual(reasons, append([]string{}, test.reasons...)) {
+				t.Errorf("expected %q, got %v %q", test.reasons, ok, reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// BugStateRule refuse pull requests with bugs in unexpected status or targeted at different release than the one being triaged.
+type BugStateRule struct {
+	Config *config.BugStateRuleConfig
+	// Release is the release being triaged (eg. 4.7)
+	Release string
+}
+
+func (b *BugStateRule) Name() string {
+	return "bugState"
+}
+
+func (b *BugStateRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
+	result := []string{}
+	bug := pullRequest.Bug()
+
+	if len(b.Config.AllowedStatuses) > 0 {
+		allowed := false
+		for _, s := range b.Config.AllowedStatuses {
+			if strings.EqualFold(s, bug.Status) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			result = append(result, fmt.Sprintf("skipping because bug status is %s (allowed: %s)", bug.Status, strings.Join(b.Config.AllowedStatuses, ", ")))
+		}
+	}
+
+	if len(b.Config.TargetRelease) > 0 {
+		expected := strings.ReplaceAll(b.Config.TargetRelease, "{release}", b.Release)
+		found := false
+		for _, t := range bug.TargetRelease {
+			if t == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, fmt.Sprintf("skipping because bug target release is %q, expected %q", strings.Join(bug.TargetRelease, ","), expected))
+		}
+	}
+
+	return result, len(result) == 0
+}
@@ -0,0 +1,62 @@
+package rule
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestBugStateRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.BugStateRuleConfig
+		bug     tracker.Issue
+		reasons []string
+	}{
+		{
+			name: "not configured",
+			bug:  tracker.Issue{Status: "NEW"},
+		},
+		{
+			name:   "allowed status",
+			config: config.BugStateRuleConfig{AllowedStatuses: []string{"POST", "MODIFIED"}},
+			bug:    tracker.Issue{Status: "modified"},
+		},
+		{
+			name:    "status not allowed",
+			config:  config.BugStateRuleConfig{AllowedStatuses: []string{"POST", "MODIFIED"}},
+			bug:     tracker.Issue{Status: "ON_QA"},
+			reasons: []string{"skipping because bug status is ON_QA (allowed: POST, MODIFIED)"},
+		},
+		{
+			name:   "target release of the triaged release",
+			config: config.BugStateRuleConfig{TargetRelease: "{release}.z"},
+			bug:    tracker.Issue{TargetRelease: []string{"4.6.z", "4.7.z"}},
+		},
+		{
+			name:    "target release of other release",
+			config:  config.BugStateRuleConfig{TargetRelease: "{release}.z"},
+			bug:     tracker.Issue{TargetRelease: []string{"4.8.0"}},
+			reasons: []string{`skipping because bug target release is "4.8.0", expected "4.7.z"`},
+		},
+		{
+			name:    "both status and target release wrong",
+			config:  config.BugStateRuleConfig{AllowedStatuses: []string{"POST"}, TargetRelease: "{release}.z"},
+			bug:     tracker.Issue{Status: "NEW"},
+			reasons: []string{"skipping because bug status is NEW (allowed: POST)", `skipping because bug target release is "", expected "4.7.z"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := &BugStateRule{Config: &test.config, Release: "4.7"}
+			bug := test.bug
+			reasons, ok := rule.Evaluate(fake.NewPullRequest(fake.PullRequest{Bug: &bug}))
+			if ok != (len(test.reasons) == 0) || !reflect.DeepEqual(reasons, append([]string{}, test.reasons...)) {
+				t.Errorf("expected %q, got %v %q", test.reasons, ok, reasons)
+			}
+		})
+	}
+}
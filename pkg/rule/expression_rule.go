@@ -0,0 +1,61 @@
+package rule
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/expression"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// ExpressionRule refuse pull requests matching CEL expression from the config.
+type ExpressionRule struct {
+	name       string
+	expression *expression.Expression
+	message    *template.Template
+	// now is the time of the run the expression is evaluated at
+	now time.Time
+}
+
+// NewExpressionRule compiles the expression and the message template of the rule evaluated at given time of the run.
+func NewExpressionRule(c *config.ExpressionRuleConfig, now time.Time) (*ExpressionRule, error) {
+	compiled, err := expression.Compile(c.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %v", c.Name, err)
+	}
+	message := c.Message
+	if len(message) == 0 {
+		message = fmt.Sprintf("skipping because %s", c.Expression)
+	}
+	messageTemplate, err := template.New(c.Name).Option("missingkey=error").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: invalid message template: %v", c.Name, err)
+	}
+	name := c.Name
+	if len(name) == 0 {
+		name = "expression"
+	}
+	return &ExpressionRule{name: name, expression: compiled, message: messageTemplate, now: now}, nil
+}
+
+func (e *ExpressionRule) Name() string {
+	return e.name
+}
+
+func (e *ExpressionRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
+	refuse, err := e.expression.Evaluate(pullRequest, e.now)
+	if err != nil {
+		return []string{fmt.Sprintf("skipping because rule %s failed: %v", e.name, err)}, false
+	}
+	if !refuse {
+		return nil, true
+	}
+	message := &bytes.Buffer{}
+	if err := e.message.Execute(message, expression.Variables(pullRequest, e.now)); err != nil {
+		return []string{fmt.Sprintf("skipping because %s (invalid message: %v)", e.expression, err)}, false
+	}
+	return []string{message.String()}, false
+}
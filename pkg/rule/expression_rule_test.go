@@ -0,0 +1,101 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/v32/github"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github/fake"
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+func TestExpressionRule(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-61 * 24 * time.Hour)
+	pullRequest := fake.NewPullRequest(fake.PullRequest{
+		Issue: &githubapi.Issue{HTMLURL: githubapi.String("https://github.com/openshift/installer/pull/1"), CreatedAt: &created},
+		Bug:   &tracker.Issue{ID: "1", Severity: "low"},
+	})
+	tests := []struct {
+		name     string
+		config   config.ExpressionRuleConfig
+		now      time.Time
+		reasons  []string
+		expected bool
+	}{
+		{
+			name:     "no match",
+			config:   config.ExpressionRuleConfig{Name: "kubernetes", Expression: `repo == "openshift/kubernetes"`},
+			now:      now,
+			expected: true,
+		},
+		{
+			name: "match with message template",
+			config: config.ExpressionRuleConfig{
+				Name:       "installerLowSeverity",
+				Expression: `repo == "openshift/installer" && severity == "low"`,
+				Message:    "skipping because {{.repo}} accepts only fixes for bugs with severity higher than {{.severity}}",
+			},
+			now:     now,
+			reasons: []string{"skipping because openshift/installer accepts only fixes for bugs with severity higher than low"},
+		},
+		{
+			name:    "match without message",
+			config:  config.ExpressionRuleConfig{Expression: `severity == "low"`},
+			now:     now,
+			reasons: []string{`skipping because severity == "low"`},
+		},
+		{
+			name:    "match with missing key in message",
+			config:  config.ExpressionRuleConfig{Name: "low", Expression: `severity == "low"`, Message: "skipping {{.unknown}}"},
+			now:     now,
+			reasons: []string{`skipping because severity == "low" (invalid message: template: low:1:11: executing "low" at <.unknown>: map has no entry for key "unknown")`},
+		},
+		{
+			name:    "age at the time of the run",
+			config:  config.ExpressionRuleConfig{Name: "stale", Expression: `ageDays > 60`, Message: "skipping because pull request is {{.ageDays}} days old"},
+			now:     now,
+			reasons: []string{"skipping because pull request is 61 days old"},
+		},
+		{
+			name:     "age at the time of earlier run",
+			config:   config.ExpressionRuleConfig{Name: "stale", Expression: `ageDays > 60`},
+			now:      now.Add(-2 * 24 * time.Hour),
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := NewRulesFromConfig(&config.RulesConfig{ExpressionRules: []config.ExpressionRuleConfig{test.config}}, "4.7", test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := rules[len(rules)-1]
+			reasons, ok := rule.Evaluate(pullRequest)
+			if ok != test.expected || !reflect.DeepEqual(reasons, test.reasons) {
+				t.Errorf("expected %v %q, got %v %q", test.expected, test.reasons, ok, reasons)
+			}
+		})
+	}
+}
+
+func TestExpressionRuleErrors(t *testing.T) {
+	tests := []struct {
+		config config.ExpressionRuleConfig
+		err    string
+	}{
+		{config: config.ExpressionRuleConfig{Name: "syntax", Expression: `severity ==`}, err: `rule "syntax": invalid expression`},
+		{config: config.ExpressionRuleConfig{Name: "notBool", Expression: `severity`}, err: `rule "notBool": expression "severity" must return bool`},
+		{config: config.ExpressionRuleConfig{Name: "message", Expression: `severity == "low"`, Message: "{{.severity"}, err: `rule "message": invalid message template`},
+	}
+	for _, test := range tests {
+		_, err := NewRulesFromConfig(&config.RulesConfig{ExpressionRules: []config.ExpressionRuleConfig{test.config}}, "4.7", time.Now())
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%s: expected error %q, got %v", test.config.Name, test.err, err)
+		}
+	}
+}
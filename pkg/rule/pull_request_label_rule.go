@@ -12,6 +12,10 @@ type PullRequestLabelRule struct {
 	Config *config.PullRequestLabelRuleConfig
 }
 
+func (p *PullRequestLabelRule) Name() string {
+	return "labels"
+}
+
 func (p *PullRequestLabelRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
 	result := []string{}
 	for _, l := range pullRequest.Issue.Labels {
@@ -1,8 +1,15 @@
 package rule
 
-import "github.com/openshift/patchmanager/pkg/github"
+import (
+	"time"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
 
 type Ruler interface {
+	// Name is used to identify the rule verdict
+	Name() string
 	Evaluate(*github.PullRequest) ([]string, bool)
 }
 
@@ -10,6 +17,15 @@ type MultiRuler struct {
 	rulers []Ruler
 }
 
+func (m *MultiRuler) Name() string {
+	return "multi"
+}
+
+// Rules returns all rules this ruler evaluate.
+func (m *MultiRuler) Rules() []Ruler {
+	return m.rulers
+}
+
 func (m *MultiRuler) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
 	decisions := []string{}
 	result := true
@@ -26,3 +42,23 @@ func (m *MultiRuler) Evaluate(pullRequest *github.PullRequest) ([]string, bool)
 func NewMultiRuler(rullers ...Ruler) Ruler {
 	return &MultiRuler{rulers: rullers}
 }
+
+// NewRulesFromConfig returns all rules configured in the rules config for the release being triaged, evaluated at given
+// time of the run.
+func NewRulesFromConfig(c *config.RulesConfig, release string, now time.Time) ([]Ruler, error) {
+	rules := []Ruler{
+		&PullRequestLabelRule{Config: &c.PullRequestLabelConfig},
+		&BugFlagsRule{Config: &c.BugFlagsConfig},
+		&BugStateRule{Config: &c.BugStateConfig, Release: release},
+		&UpstreamFixRule{Config: &c.UpstreamFixConfig},
+		&BackportChainRule{Config: &c.BackportChainConfig, Release: release},
+	}
+	for i := range c.ExpressionRules {
+		expressionRule, err := NewExpressionRule(&c.ExpressionRules[i], now)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, expressionRule)
+	}
+	return rules, nil
+}
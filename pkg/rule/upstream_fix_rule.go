@@ -0,0 +1,33 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/patchmanager/pkg/config"
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// UpstreamFixRule refuse backports which parent bug (the bug in the next release it was cloned from) was not verified yet.
+type UpstreamFixRule struct {
+	Config *config.UpstreamFixRuleConfig
+}
+
+func (u *UpstreamFixRule) Name() string {
+	return "upstreamFix"
+}
+
+func (u *UpstreamFixRule) Evaluate(pullRequest *github.PullRequest) ([]string, bool) {
+	if !u.Config.Required {
+		return nil, true
+	}
+	id, parent, err := pullRequest.UnverifiedParentBug(u.Config.VerifiedStatuses)
+	if err != nil {
+		return []string{fmt.Sprintf("skipping because parent bug %s can't be fetched: %v", id, err)}, false
+	}
+	if parent != nil {
+		status := strings.TrimSpace(parent.Status + " " + parent.Resolution)
+		return []string{fmt.Sprintf("skipping because parent bug %s is %s, the fix must be verified in the next release first", id, status)}, false
+	}
+	return nil, true
+}
@@ -43,6 +43,14 @@ func NewWorkerPool(classifier classifiers.Classifier) Pool {
 		if !ok {
 			return true
 		}
+		// pull requests with bugs that failed to fetch are not classified, they get "error" decision
+		if pull.Bug() == nil {
+			return true
+		}
+		if breakdown, ok := p.classifier.(classifiers.BreakdownClassifier); ok {
+			pull.Score, pull.ScoreBreakdown = breakdown.ScoreBreakdown(pull)
+			return true
+		}
 		pull.Score = p.classifier.Score(pull)
 		return true
 	})
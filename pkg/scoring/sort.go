@@ -0,0 +1,15 @@
+package scoring
+
+import (
+	"sort"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+// SortByScore orders the pull requests by score (highest first). Pull requests with the same score keep the order they
+// were listed in, so the run and explain commands rank them the same way.
+func SortByScore(pulls []*github.PullRequest) {
+	sort.SliceStable(pulls, func(i, j int) bool {
+		return pulls[i].Score > pulls[j].Score
+	})
+}
@@ -0,0 +1,18 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/openshift/patchmanager/pkg/github"
+)
+
+func TestSortByScore(t *testing.T) {
+	pulls := []*github.PullRequest{{Score: 0.5}, {Score: 1}, {Score: 0.5}, {Score: -1}, {Score: 1}}
+	expected := []*github.PullRequest{pulls[1], pulls[4], pulls[0], pulls[2], pulls[3]}
+	SortByScore(pulls)
+	for i := range expected {
+		if pulls[i] != expected[i] {
+			t.Errorf("position %d: expected pull request with score %g, got %g", i, expected[i].Score, pulls[i].Score)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SearchKind stores Github search result pages.
+	SearchKind = "search"
+	// StatusKind stores pull request statuses.
+	StatusKind = "statuses"
+	// IssueKind stores individually fetched pull requests.
+	IssueKind = "issues"
+	// BugKind stores bugs fetched from issue trackers.
+	BugKind = "bugs"
+	// PullKind stores pull request details.
+	PullKind = "pulls"
+	// FilesKind stores files changed by pull requests.
+	FilesKind = "files"
+)
+
+// ConfigFile is the name of the file in the snapshot directory that holds the config used for the recorded run.
+const ConfigFile = "config.yaml"
+
+// ReleaseConfigFile is the name of the file that holds the config of a single release in a multi-release run, where
+// "{release}" is replaced by the release.
+const ReleaseConfigFile = "config-{release}.yaml"
+
+// RunTimeFile is the name of the file that holds the time of the recorded run, time dependent scores and rules (eg. the
+// pull request age) are evaluated against it when replaying.
+const RunTimeFile = "time.json"
+
+// Store persist everything fetched from Github and issue trackers during a run to a directory (record mode) or serve
+// the previously recorded data back without any network access (replay mode).
+type Store struct {
+	dir    string
+	replay bool
+	now    time.Time
+}
+
+// NewRecorder returns store that record all fetched data into given directory.
+func NewRecorder(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	store := &Store{dir: dir, now: time.Now()}
+	content, err := json.Marshal(store.now)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(store.Path(RunTimeFile), content, 0644); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// NewReplayer returns store that serve data previously recorded in given directory.
+func NewReplayer(dir string) (*Store, error) {
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("snapshot %q is not a directory", dir)
+	}
+	store := &Store{dir: dir, replay: true}
+	content, err := ioutil.ReadFile(store.Path(RunTimeFile))
+	switch {
+	case os.IsNotExist(err):
+		klog.Warningf("WARNING: snapshot %q does not record the time of the run, the current time is used", dir)
+		store.now = time.Now()
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(content, &store.now); err != nil {
+			return nil, fmt.Errorf("invalid run time in snapshot %q: %v", dir, err)
+		}
+	}
+	return store, nil
+}
+
+// Replaying returns true when the data should be loaded from the snapshot instead of being fetched.
+func (s *Store) Replaying() bool {
+	return s != nil && s.replay
+}
+
+// Now returns the time of the run: the recorded time when replaying, otherwise the current time.
+func (s *Store) Now() time.Time {
+	if s == nil {
+		return time.Now()
+	}
+	return s.now
+}
+
+// Recording returns true when the fetched data should be saved to the snapshot.
+func (s *Store) Recording() bool {
+	return s != nil && !s.replay
+}
+
+// Path returns the path to the file in the snapshot directory.
+func (s *Store) Path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// Save stores the object under given kind and key.
+func (s *Store) Save(kind, key string, obj interface{}) error {
+	content, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	fileName := s.fileName(kind, key)
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, content, 0644)
+}
+
+// Load reads the object stored under given kind and key.
+func (s *Store) Load(kind, key string, obj interface{}) error {
+	content, err := ioutil.ReadFile(s.fileName(kind, key))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s %q was not recorded in snapshot %q", kind, key, s.dir)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, obj)
+}
+
+// SaveConfig stores the raw config used for the run.
+func (s *Store) SaveConfig(content []byte) error {
+	return ioutil.WriteFile(s.Path(ConfigFile), content, 0644)
+}
+
+// SaveReleaseConfig stores the raw config used for the release in a multi-release run.
+func (s *Store) SaveReleaseConfig(release string, content []byte) error {
+	return ioutil.WriteFile(s.Path(strings.ReplaceAll(ReleaseConfigFile, "{release}", release)), content, 0644)
+}
+
+// fileName returns file name for given kind and key. Keys that are not safe to use as file names (eg. search queries) are hashed.
+func (s *Store) fileName(kind, key string) string {
+	safeKey := strings.NewReplacer("/", "_", ":", "_").Replace(key)
+	if strings.ContainsAny(safeKey, " \"*?<>|") || len(safeKey) > 128 {
+		safeKey = fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+	}
+	return filepath.Join(s.dir, kind, safeKey+".json")
+}
@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/patchmanager/pkg/tracker"
+)
+
+type snapshotTracker struct {
+	store   *Store
+	tracker tracker.Tracker
+}
+
+var _ tracker.BatchTracker = &snapshotTracker{}
+
+// WrapTracker returns tracker that record every fetched issue into the store or, when replaying, serve issues from the store
+// without calling the wrapped tracker at all.
+func WrapTracker(store *Store, t tracker.Tracker) tracker.Tracker {
+	return &snapshotTracker{store: store, tracker: t}
+}
+
+func (s *snapshotTracker) Name() string {
+	return s.tracker.Name()
+}
+
+func (s *snapshotTracker) key(id string) string {
+	return s.tracker.Name() + "/" + id
+}
+
+func (s *snapshotTracker) GetIssue(id string) (*tracker.Issue, error) {
+	if s.store.Replaying() {
+		var issue tracker.Issue
+		if err := s.store.Load(BugKind, s.key(id), &issue); err != nil {
+			// bugs that are not in snapshot were not accessible during the recorded run, there is no point in retrying
+			return nil, &tracker.NotAccessibleError{Tracker: s.Name(), ID: id, Err: err}
+		}
+		return &issue, nil
+	}
+	issue, err := s.tracker.GetIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	s.record(issue)
+	return issue, nil
+}
+
+func (s *snapshotTracker) GetIssues(ids []string) (map[string]*tracker.Issue, error) {
+	result := map[string]*tracker.Issue{}
+	if s.store.Replaying() {
+		for _, id := range ids {
+			if issue, err := s.GetIssue(id); err == nil {
+				result[id] = issue
+			}
+		}
+		return result, nil
+	}
+
+	batchTracker, ok := s.tracker.(tracker.BatchTracker)
+	if !ok {
+		// the batch is only an optimization, issues will be fetched (and recorded) one by one
+		return result, nil
+	}
+	result, err := batchTracker.GetIssues(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range result {
+		s.record(issue)
+	}
+	return result, nil
+}
+
+func (s *snapshotTracker) record(issue *tracker.Issue) {
+	if err := s.store.Save(BugKind, s.key(issue.ID), issue); err != nil {
+		klog.Warningf("WARNING: Failed to record bug %s to snapshot: %v", issue.ID, err)
+	}
+}
@@ -0,0 +1,128 @@
+package tracker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// BugzillaEndpoint is the Red Hat Bugzilla instance used by default.
+const BugzillaEndpoint = "https://bugzilla.redhat.com"
+
+// bugzillaIncludeFields are the bug fields fetched from Bugzilla, external bugs are not included by default.
+var bugzillaIncludeFields = []string{"_default", "flags", "external_bugs"}
+
+// bugzillaCustomerCaseType is the external tracker type of Red Hat Customer Portal support cases.
+const bugzillaCustomerCaseType = "SFDC"
+
+// bugzillaGithubURL is the external tracker URL of Github pull requests.
+const bugzillaGithubURL = "https://github.com/"
+
+// bugzillaBatchSize is the maximum number of bugs fetched by single search request, to keep the request URL reasonably short.
+const bugzillaBatchSize = 100
+
+type bugzillaTracker struct {
+	client bugzilla.Client
+}
+
+var _ BatchTracker = &bugzillaTracker{}
+
+// NewBugzillaTracker returns tracker that fetch bugs from Bugzilla using given API key.
+func NewBugzillaTracker(apiKey string) Tracker {
+	return NewBugzillaTrackerWithClient(bugzilla.NewClient(func() []byte {
+		return []byte(apiKey)
+	}, BugzillaEndpoint))
+}
+
+// NewBugzillaTrackerWithClient returns tracker that use existing Bugzilla client (eg. fake client).
+func NewBugzillaTrackerWithClient(client bugzilla.Client) Tracker {
+	return &bugzillaTracker{client: client}
+}
+
+func (b *bugzillaTracker) Name() string {
+	return Bugzilla
+}
+
+func (b *bugzillaTracker) GetIssue(id string) (*Issue, error) {
+	bugID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bugzilla bug number %q: %v", id, err)
+	}
+	// search is used instead of GetBug as it allows to include the external bugs in the same request
+	issues, err := b.GetIssues([]string{strconv.Itoa(bugID)})
+	if err != nil {
+		return nil, err
+	}
+	issue, ok := issues[strconv.Itoa(bugID)]
+	if !ok {
+		// private bugs are silently omitted from search results
+		return nil, &NotAccessibleError{Tracker: Bugzilla, ID: id, Err: fmt.Errorf("bug not found or private")}
+	}
+	return issue, nil
+}
+
+func (b *bugzillaTracker) GetIssues(ids []string) (map[string]*Issue, error) {
+	result := map[string]*Issue{}
+	for start := 0; start < len(ids); start += bugzillaBatchSize {
+		end := start + bugzillaBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		bugs, err := b.client.Search(bugzilla.Query{
+			BugIDs:        ids[start:end],
+			BugIDsType:    "anyexact",
+			IncludeFields: bugzillaIncludeFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range bugs {
+			issue := fromBugzillaBug(b.client.Endpoint(), bugs[i])
+			result[issue.ID] = issue
+		}
+	}
+	return result, nil
+}
+
+func fromBugzillaBug(endpoint string, bug *bugzilla.Bug) *Issue {
+	issue := &Issue{
+		ID:         strconv.Itoa(bug.ID),
+		Tracker:    Bugzilla,
+		URL:        fmt.Sprintf("%s/show_bug.cgi?id=%d", endpoint, bug.ID),
+		Summary:    bug.Summary,
+		Status:     bug.Status,
+		Resolution: bug.Resolution,
+		Severity:   bug.Severity,
+		Priority:   bug.Priority,
+		Keywords:   bug.Keywords,
+		Component:  bug.Component,
+		PMScore:    bug.PMScore,
+		Whiteboard: bug.Whiteboard,
+		Escalated:  strings.EqualFold(bug.Escalation, "yes"),
+
+		TargetRelease: bug.TargetRelease,
+	}
+	for _, id := range bug.DependsOn {
+		issue.DependsOn = append(issue.DependsOn, strconv.Itoa(id))
+	}
+	for _, id := range bug.Blocks {
+		issue.Blocks = append(issue.Blocks, strconv.Itoa(id))
+	}
+	for _, flag := range bug.Flags {
+		issue.Flags = append(issue.Flags, flag.Name+flag.Status)
+	}
+	for _, externalBug := range bug.ExternalBugs {
+		if externalBug.Type.Type == bugzillaCustomerCaseType {
+			issue.CustomerCases = append(issue.CustomerCases, externalBug.ExternalBugID)
+		}
+		// same filter as bugzilla client GetExternalBugPRsOnBug() use, the external bugs are already fetched with the bug
+		if externalBug.Type.URL == bugzillaGithubURL {
+			if org, repo, number, err := bugzilla.PullFromIdentifier(externalBug.ExternalBugID); err == nil {
+				issue.PullRequests = append(issue.PullRequests, fmt.Sprintf("%s%s/%s/pull/%d", bugzillaGithubURL, org, repo, number))
+			}
+		}
+	}
+	return issue
+}
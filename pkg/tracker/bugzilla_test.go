@@ -0,0 +1,68 @@
+package tracker
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+// searchFake is the fake Bugzilla client that search only the requested bugs (the vendored fake returns all bugs) and
+// records the bug IDs of every search.
+type searchFake struct {
+	*bugzilla.Fake
+	searches [][]string
+}
+
+func (f *searchFake) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	f.searches = append(f.searches, query.BugIDs)
+	bugs := []*bugzilla.Bug{}
+	for _, id := range query.BugIDs {
+		bugID, _ := strconv.Atoi(id)
+		if bug, ok := f.Bugs[bugID]; ok {
+			bugs = append(bugs, &bug)
+		}
+	}
+	return bugs, nil
+}
+
+func TestBugzillaGetIssuesBatches(t *testing.T) {
+	client := &searchFake{Fake: &bugzilla.Fake{EndpointString: "https://bugzilla.example.com", Bugs: map[int]bugzilla.Bug{}}}
+	ids := []string{}
+	for i := 1; i <= 250; i++ {
+		ids = append(ids, strconv.Itoa(i))
+		// every tenth bug is private
+		if i%10 != 0 {
+			client.Bugs[i] = bugzilla.Bug{ID: i, Summary: "bug " + strconv.Itoa(i)}
+		}
+	}
+
+	issues, err := NewBugzillaTrackerWithClient(client).(BatchTracker).GetIssues(ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.searches) != 3 {
+		t.Fatalf("expected 3 searches, got %d", len(client.searches))
+	}
+	for i, expected := range []int{100, 100, 50} {
+		if len(client.searches[i]) != expected {
+			t.Errorf("expected search %d to request %d bugs, got %d", i, expected, len(client.searches[i]))
+		}
+	}
+	if len(issues) != 225 {
+		t.Errorf("expected 225 accessible bugs, got %d", len(issues))
+	}
+	if _, ok := issues["10"]; ok {
+		t.Errorf("expected private bug 10 to be left out")
+	}
+	if issue := issues["11"]; issue == nil || issue.Summary != "bug 11" || issue.URL != "https://bugzilla.example.com/show_bug.cgi?id=11" {
+		t.Errorf("unexpected bug 11: %+v", issue)
+	}
+}
+
+func TestBugzillaGetIssueNotAccessible(t *testing.T) {
+	client := &searchFake{Fake: &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{}}}
+	if _, err := NewBugzillaTrackerWithClient(client).GetIssue("1"); !IsNotAccessible(err) {
+		t.Errorf("expected not accessible error for private bug, got %v", err)
+	}
+}
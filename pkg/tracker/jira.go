@@ -0,0 +1,224 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// JiraEndpoint is the Red Hat Jira instance used by default.
+const JiraEndpoint = "https://issues.redhat.com"
+
+// jiraSeverityField is the custom field OCPBUGS project use to store the bug severity.
+const jiraSeverityField = "customfield_12316142"
+
+// jiraTargetVersionField is the custom field OCPBUGS project use to store the target release.
+const jiraTargetVersionField = "customfield_12319940"
+
+// jiraSeverities translate Jira severity names to the Bugzilla ones, so the severity classifier config works for both trackers.
+var jiraSeverities = map[string]string{
+	"critical":  "urgent",
+	"important": "high",
+	"moderate":  "medium",
+	"low":       "low",
+}
+
+// jiraPriorities translate Jira priority names to the Bugzilla ones, so the priority classifier config works for both trackers.
+var jiraPriorities = map[string]string{
+	"blocker":   "urgent",
+	"critical":  "urgent",
+	"major":     "high",
+	"normal":    "medium",
+	"minor":     "low",
+	"undefined": "unspecified",
+}
+
+type jiraTracker struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+var _ Tracker = &jiraTracker{}
+
+// NewJiraTracker returns tracker that fetch issues from Jira REST API using given personal access token.
+func NewJiraTracker(endpoint, token string) Tracker {
+	return &jiraTracker{
+		client:   &http.Client{},
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+	}
+}
+
+func (j *jiraTracker) Name() string {
+	return Jira
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Resolution *struct {
+			Name string `json:"name"`
+		} `json:"resolution"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		IssueLinks []jiraIssueLink `json:"issuelinks"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Labels   []string `json:"labels"`
+		Severity *struct {
+			Value string `json:"value"`
+		} `json:"customfield_12316142"`
+		TargetVersion []struct {
+			Name string `json:"name"`
+		} `json:"customfield_12319940"`
+	} `json:"fields"`
+}
+
+// jiraIssueLink is a link between two issues, only one of the inward and outward issue is set.
+type jiraIssueLink struct {
+	Type struct {
+		Name string `json:"name"`
+	} `json:"type"`
+	InwardIssue *struct {
+		Key string `json:"key"`
+	} `json:"inwardIssue,omitempty"`
+	OutwardIssue *struct {
+		Key string `json:"key"`
+	} `json:"outwardIssue,omitempty"`
+}
+
+// jiraBlocksLink is the issue link type used between z-stream backports and the bugs they were cloned from.
+const jiraBlocksLink = "Blocks"
+
+// jiraRemoteLink is a link from the issue to an external resource (eg. Github pull request).
+type jiraRemoteLink struct {
+	Object struct {
+		URL string `json:"url"`
+	} `json:"object"`
+}
+
+// jiraPullRequestURLRe matches remote links to Github pull requests.
+var jiraPullRequestURLRe = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+
+func (j *jiraTracker) GetIssue(id string) (*Issue, error) {
+	values := url.Values{}
+	values.Add("fields", strings.Join([]string{"summary", "status", "resolution", "priority", "issuelinks", "components", "labels", jiraSeverityField, jiraTargetVersionField}, ","))
+	var issue jiraIssue
+	switch code, err := j.get(fmt.Sprintf("%s/rest/api/2/issue/%s?%s", j.endpoint, id, values.Encode()), &issue); code {
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return nil, &NotAccessibleError{Tracker: Jira, ID: id, Err: err}
+	default:
+		if err != nil {
+			return nil, fmt.Errorf("jira issue %s: %v", id, err)
+		}
+	}
+	result := fromJiraIssue(j.endpoint, &issue)
+	// pull requests are linked to Jira issues as remote links, not as issue fields
+	var err error
+	if result.PullRequests, err = j.getPullRequests(id); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getPullRequests returns URLs of Github pull requests linked to the issue.
+func (j *jiraTracker) getPullRequests(id string) ([]string, error) {
+	var links []jiraRemoteLink
+	if _, err := j.get(fmt.Sprintf("%s/rest/api/2/issue/%s/remotelink", j.endpoint, id), &links); err != nil {
+		return nil, fmt.Errorf("jira issue %s remote links: %v", id, err)
+	}
+	var result []string
+	for _, link := range links {
+		if matches := jiraPullRequestURLRe.FindStringSubmatch(link.Object.URL); len(matches) > 0 {
+			result = append(result, fmt.Sprintf("https://github.com/%s/%s/pull/%s", matches[1], matches[2], matches[3]))
+		}
+	}
+	return result, nil
+}
+
+// get fetch the JSON object from the Jira REST API and returns the response code together with the error.
+func (j *jiraTracker) get(requestURL string, obj interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(j.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("response code %d not %d", resp.StatusCode, http.StatusOK)
+	}
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("could not read response body: %v", err)
+	}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return resp.StatusCode, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return resp.StatusCode, nil
+}
+
+func fromJiraIssue(endpoint string, issue *jiraIssue) *Issue {
+	result := &Issue{
+		ID:       issue.Key,
+		Tracker:  Jira,
+		URL:      fmt.Sprintf("%s/browse/%s", endpoint, issue.Key),
+		Summary:  issue.Fields.Summary,
+		Status:   issue.Fields.Status.Name,
+		Keywords: issue.Fields.Labels,
+	}
+	if issue.Fields.Resolution != nil {
+		result.Resolution = issue.Fields.Resolution.Name
+	}
+	for _, link := range issue.Fields.IssueLinks {
+		if link.Type.Name != jiraBlocksLink {
+			continue
+		}
+		// this issue "is blocked by" the inward issue and "blocks" the outward issue
+		if link.InwardIssue != nil {
+			result.DependsOn = append(result.DependsOn, link.InwardIssue.Key)
+		}
+		if link.OutwardIssue != nil {
+			result.Blocks = append(result.Blocks, link.OutwardIssue.Key)
+		}
+	}
+	for _, v := range issue.Fields.TargetVersion {
+		result.TargetRelease = append(result.TargetRelease, v.Name)
+	}
+	for _, c := range issue.Fields.Components {
+		result.Component = append(result.Component, c.Name)
+	}
+	if issue.Fields.Priority != nil {
+		priority := strings.ToLower(issue.Fields.Priority.Name)
+		if p, ok := jiraPriorities[priority]; ok {
+			priority = p
+		}
+		result.Priority = priority
+	}
+	if issue.Fields.Severity != nil {
+		severity := strings.ToLower(issue.Fields.Severity.Value)
+		if s, ok := jiraSeverities[severity]; ok {
+			severity = s
+		}
+		result.Severity = severity
+	}
+	return result
+}
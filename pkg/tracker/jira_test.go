@@ -0,0 +1,246 @@
+package tracker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newJiraTestServer returns a local stand-in for the Jira REST API that serves given raw JSON responses.
+// Responses are indexed by the path after /rest/api/2/issue/ (eg. OCPBUGS-1234 or OCPBUGS-1234/remotelink), paths
+// without a response get the Jira "Issue Does Not Exist" error.
+// Use NewJiraTracker(server.URL, "") to get a tracker backed by this server.
+func newJiraTestServer(responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		response, ok := responses[key]
+		if r.Method != http.MethodGet || key == r.URL.Path || !ok {
+			http.Error(w, `{"errorMessages":["Issue Does Not Exist"],"errors":{}}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+// jiraIssueFixture is trimmed response of GET /rest/api/2/issue/OCPBUGS-2 with the fields the Jira tracker asks for.
+const jiraIssueFixture = `{
+  "expand": "renderedFields,names,schema,operations,editmeta,changelog,versionedRepresentations",
+  "id": "14885002",
+  "self": "https://issues.redhat.com/rest/api/2/issue/14885002",
+  "key": "OCPBUGS-2",
+  "fields": {
+    "summary": "etcd leader election is slow",
+    "status": {
+      "self": "https://issues.redhat.com/rest/api/2/status/12316244",
+      "description": "",
+      "name": "ON_QA",
+      "id": "12316244",
+      "statusCategory": {"id": 4, "key": "indeterminate", "colorName": "yellow", "name": "In Progress"}
+    },
+    "resolution": {
+      "self": "https://issues.redhat.com/rest/api/2/resolution/1",
+      "id": "1",
+      "description": "Work has been completed on this issue.",
+      "name": "Done"
+    },
+    "priority": {
+      "self": "https://issues.redhat.com/rest/api/2/priority/3",
+      "iconUrl": "https://issues.redhat.com/images/icons/priorities/major.svg",
+      "name": "Major",
+      "id": "3"
+    },
+    "issuelinks": [
+      {
+        "id": "16401223",
+        "type": {"id": "12310000", "name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+        "inwardIssue": {"id": "14885001", "key": "OCPBUGS-1", "fields": {"summary": "etcd leader election is slow"}}
+      },
+      {
+        "id": "16401224",
+        "type": {"id": "12310000", "name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+        "outwardIssue": {"id": "14885003", "key": "OCPBUGS-3", "fields": {"summary": "etcd leader election is slow"}}
+      },
+      {
+        "id": "16401225",
+        "type": {"id": "12310001", "name": "Cloners", "inward": "is cloned by", "outward": "clones"},
+        "outwardIssue": {"id": "14885005", "key": "OCPBUGS-5", "fields": {"summary": "etcd leader election is slow"}}
+      }
+    ],
+    "components": [
+      {"self": "https://issues.redhat.com/rest/api/2/component/12367617", "id": "12367617", "name": "Etcd"}
+    ],
+    "labels": ["TestBlocker"],
+    "customfield_12316142": {
+      "self": "https://issues.redhat.com/rest/api/2/customFieldOption/26228",
+      "value": "Important",
+      "id": "26228",
+      "disabled": false
+    },
+    "customfield_12319940": [
+      {
+        "self": "https://issues.redhat.com/rest/api/2/version/12390358",
+        "id": "12390358",
+        "name": "4.12.z",
+        "archived": false,
+        "released": false
+      }
+    ]
+  }
+}`
+
+// jiraRemoteLinksFixture is response of GET /rest/api/2/issue/OCPBUGS-2/remotelink, only Github pull requests are used.
+const jiraRemoteLinksFixture = `[
+  {
+    "id": 615001,
+    "self": "https://issues.redhat.com/rest/api/2/issue/OCPBUGS-2/remotelink/615001",
+    "object": {"url": "https://access.redhat.com/solutions/1", "title": "Knowledge base article"}
+  },
+  {
+    "id": 615002,
+    "self": "https://issues.redhat.com/rest/api/2/issue/OCPBUGS-2/remotelink/615002",
+    "globalId": "https://github.com/openshift/cluster-etcd-operator/pull/12",
+    "application": {},
+    "relationship": "links to",
+    "object": {
+      "url": "https://github.com/openshift/cluster-etcd-operator/pull/12/files",
+      "title": "GitHub Pull Request #12",
+      "icon": {}
+    }
+  }
+]`
+
+func TestJiraGetIssue(t *testing.T) {
+	server := newJiraTestServer(map[string]string{
+		"OCPBUGS-2":            jiraIssueFixture,
+		"OCPBUGS-2/remotelink": jiraRemoteLinksFixture,
+		// unset fields are null in the response
+		"OCPBUGS-4": `{"key":"OCPBUGS-4","fields":{"summary":"","status":{"name":"New"},"resolution":null,
+			"priority":{"name":"Undefined"},"issuelinks":[],"components":[],"labels":[],
+			"customfield_12316142":{"value":"Custom"},"customfield_12319940":null}}`,
+		"OCPBUGS-4/remotelink": `[]`,
+	})
+	defer server.Close()
+	jira := NewJiraTracker(server.URL, "")
+
+	issue, err := jira.GetIssue("OCPBUGS-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &Issue{
+		ID:            "OCPBUGS-2",
+		Tracker:       Jira,
+		URL:           server.URL + "/browse/OCPBUGS-2",
+		Summary:       "etcd leader election is slow",
+		Status:        "ON_QA",
+		Resolution:    "Done",
+		Severity:      "high",
+		Priority:      "high",
+		Keywords:      []string{"TestBlocker"},
+		Component:     []string{"Etcd"},
+		TargetRelease: []string{"4.12.z"},
+		DependsOn:     []string{"OCPBUGS-1"},
+		Blocks:        []string{"OCPBUGS-3"},
+		PullRequests:  []string{"https://github.com/openshift/cluster-etcd-operator/pull/12"},
+	}
+	if !reflect.DeepEqual(issue, expected) {
+		t.Errorf("expected %+v, got %+v", expected, issue)
+	}
+
+	// unknown severities are kept lowercase, known priorities are translated
+	issue, err = jira.GetIssue("OCPBUGS-4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue.Severity != "custom" || issue.Priority != "unspecified" {
+		t.Errorf("expected severity custom and priority unspecified, got %q and %q", issue.Severity, issue.Priority)
+	}
+	if len(issue.Resolution) > 0 || len(issue.TargetRelease) > 0 || len(issue.PullRequests) > 0 {
+		t.Errorf("expected no resolution, target release and pull requests, got %+v", issue)
+	}
+}
+
+func TestJiraGetIssueNotAccessible(t *testing.T) {
+	server := newJiraTestServer(map[string]string{})
+	defer server.Close()
+	if _, err := NewJiraTracker(server.URL, "").GetIssue("OCPBUGS-1"); !IsNotAccessible(err) {
+		t.Errorf("expected not accessible error for missing issue, got %v", err)
+	}
+
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(code), code)
+		}))
+		if _, err := NewJiraTracker(server.URL, "").GetIssue("OCPBUGS-1"); !IsNotAccessible(err) {
+			t.Errorf("expected not accessible error for response code %d, got %v", code, err)
+		}
+		server.Close()
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	if _, err := NewJiraTracker(server.URL, "").GetIssue("OCPBUGS-1"); err == nil || IsNotAccessible(err) {
+		t.Errorf("expected retriable error for response code %d, got %v", http.StatusServiceUnavailable, err)
+	}
+}
+
+func TestJiraPriorities(t *testing.T) {
+	// the priority classifier config uses Bugzilla priority names as keys
+	tests := []struct {
+		key      string
+		response string
+		expected string
+	}{
+		{
+			key:      "OCPBUGS-1",
+			response: `{"key":"OCPBUGS-1","fields":{"priority":{"self":"https://issues.redhat.com/rest/api/2/priority/1","name":"Blocker","id":"1"}}}`,
+			expected: "urgent",
+		},
+		{
+			key:      "OCPBUGS-2",
+			response: `{"key":"OCPBUGS-2","fields":{"priority":{"self":"https://issues.redhat.com/rest/api/2/priority/2","name":"Critical","id":"2"}}}`,
+			expected: "urgent",
+		},
+		{
+			key:      "OCPBUGS-3",
+			response: `{"key":"OCPBUGS-3","fields":{"priority":{"self":"https://issues.redhat.com/rest/api/2/priority/10200","name":"Normal","id":"10200"}}}`,
+			expected: "medium",
+		},
+		{
+			key:      "OCPBUGS-4",
+			response: `{"key":"OCPBUGS-4","fields":{"priority":{"self":"https://issues.redhat.com/rest/api/2/priority/4","name":"Minor","id":"4"}}}`,
+			expected: "low",
+		},
+		{
+			key:      "OCPBUGS-5",
+			response: `{"key":"OCPBUGS-5","fields":{"priority":{"self":"https://issues.redhat.com/rest/api/2/priority/5","name":"Trivial","id":"5"}}}`,
+			expected: "trivial",
+		},
+		{
+			key:      "OCPBUGS-6",
+			response: `{"key":"OCPBUGS-6","fields":{"priority":null}}`,
+		},
+	}
+	responses := map[string]string{}
+	for _, test := range tests {
+		responses[test.key] = test.response
+		responses[test.key+"/remotelink"] = `[]`
+	}
+	server := newJiraTestServer(responses)
+	defer server.Close()
+	jira := NewJiraTracker(server.URL, "")
+
+	for _, test := range tests {
+		issue, err := jira.GetIssue(test.key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.key, err)
+		}
+		if issue.Priority != test.expected {
+			t.Errorf("%s: expected priority %q, got %q", test.key, test.expected, issue.Priority)
+		}
+	}
+}
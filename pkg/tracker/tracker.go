@@ -0,0 +1,119 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Bugzilla is the name of the Red Hat Bugzilla issue tracker.
+	Bugzilla = "bugzilla"
+	// Jira is the name of the Red Hat Jira issue tracker.
+	Jira = "jira"
+)
+
+// Tracker interface define how issues referenced in pull request titles are fetched from an issue tracker.
+type Tracker interface {
+	// Name returns the name of the tracker (eg. bugzilla or jira).
+	Name() string
+	// GetIssue fetch the issue with given ID and return it in normalized form.
+	GetIssue(id string) (*Issue, error)
+}
+
+// BatchTracker is implemented by trackers that are able to fetch multiple issues in a single request.
+type BatchTracker interface {
+	Tracker
+	// GetIssues fetch issues with given IDs and return them indexed by ID. Issues that were not found are not included.
+	GetIssues(ids []string) (map[string]*Issue, error)
+}
+
+// Issue is a normalized representation of a bug, regardless of the tracker it lives in.
+// Classifiers and rules should only consume this type and never the tracker specific types.
+type Issue struct {
+	// ID is the bug number in Bugzilla (eg. 1234) or the issue key in Jira (eg. OCPBUGS-1234).
+	ID string
+	// Tracker is the name of the tracker this issue was fetched from.
+	Tracker string
+	// URL is the link to the issue in the tracker web interface.
+	URL string
+
+	Summary    string
+	Status     string
+	Resolution string
+	Severity   string
+	Priority   string
+	Keywords   []string
+	Component  []string
+	PMScore    string
+	Whiteboard string
+	// TargetRelease lists releases the bug is targeted at (eg. 4.7.z).
+	TargetRelease []string
+
+	// DependsOn lists IDs of bugs this bug depends on (for z-stream backports this is the bug in the next release it was cloned from).
+	DependsOn []string
+	// Blocks lists IDs of bugs blocked by this bug (for z-stream backports these are the clones in previous releases).
+	Blocks []string
+
+	// PullRequests lists URLs of Github pull requests linked to the bug.
+	PullRequests []string
+
+	// Flags lists flags set on the bug with their status (eg. "blocker+", "requires_doc_text-").
+	Flags []string
+
+	// Escalated is set when the bug is customer facing escalation (cf_cust_facing in Bugzilla).
+	Escalated bool
+	// CustomerCases lists IDs of the customer support cases linked to the bug.
+	CustomerCases []string
+}
+
+// DefaultVerifiedStatuses lists statuses of bugs that have their fix verified by QE.
+var DefaultVerifiedStatuses = []string{"VERIFIED", "CLOSED ERRATA"}
+
+// HasStatus returns true if the issue is in one of the statuses. Statuses can include resolution (eg. "CLOSED ERRATA").
+func HasStatus(issue *Issue, statuses []string) bool {
+	for _, s := range statuses {
+		if strings.EqualFold(s, issue.Status) || strings.EqualFold(s, strings.TrimSpace(issue.Status+" "+issue.Resolution)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotAccessibleError is returned when the issue does not exist or is private and the user is not allowed to see it.
+// Fetching such issue again won't help.
+type NotAccessibleError struct {
+	Tracker string
+	ID      string
+	Err     error
+}
+
+func (e *NotAccessibleError) Error() string {
+	return fmt.Sprintf("%s issue %s is not accessible: %v", e.Tracker, e.ID, e.Err)
+}
+
+// IsNotAccessible returns true if the error means the issue does not exist or is private.
+func IsNotAccessible(err error) bool {
+	_, ok := err.(*NotAccessibleError)
+	return ok
+}
+
+// Trackers holds issue trackers indexed by their names.
+type Trackers map[string]Tracker
+
+// GetIssue fetch the issue from the named tracker.
+func (t Trackers) GetIssue(name, id string) (*Issue, error) {
+	tr, ok := t[name]
+	if !ok {
+		return nil, fmt.Errorf("issue tracker %q is not configured", name)
+	}
+	return tr.GetIssue(id)
+}
+
+// NewTrackers returns all supported trackers.
+func NewTrackers(trackers ...Tracker) Trackers {
+	result := Trackers{}
+	for i := range trackers {
+		result[trackers[i].Name()] = trackers[i]
+	}
+	return result
+}